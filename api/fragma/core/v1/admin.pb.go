@@ -0,0 +1,384 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.4
+// source: api/fragma/core/v1/admin.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GcResourceKind int32
+
+const (
+	GcResourceKind_GC_RESOURCE_KIND_MOUNT     GcResourceKind = 0
+	GcResourceKind_GC_RESOURCE_KIND_LOOP      GcResourceKind = 1
+	GcResourceKind_GC_RESOURCE_KIND_ADDRESS   GcResourceKind = 2
+	GcResourceKind_GC_RESOURCE_KIND_INTERFACE GcResourceKind = 3
+)
+
+// Enum value maps for GcResourceKind.
+var (
+	GcResourceKind_name = map[int32]string{
+		0: "GC_RESOURCE_KIND_MOUNT",
+		1: "GC_RESOURCE_KIND_LOOP",
+		2: "GC_RESOURCE_KIND_ADDRESS",
+		3: "GC_RESOURCE_KIND_INTERFACE",
+	}
+	GcResourceKind_value = map[string]int32{
+		"GC_RESOURCE_KIND_MOUNT":     0,
+		"GC_RESOURCE_KIND_LOOP":      1,
+		"GC_RESOURCE_KIND_ADDRESS":   2,
+		"GC_RESOURCE_KIND_INTERFACE": 3,
+	}
+)
+
+func (x GcResourceKind) Enum() *GcResourceKind {
+	p := new(GcResourceKind)
+	*p = x
+	return p
+}
+
+func (x GcResourceKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GcResourceKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_fragma_core_v1_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (GcResourceKind) Type() protoreflect.EnumType {
+	return &file_api_fragma_core_v1_admin_proto_enumTypes[0]
+}
+
+func (x GcResourceKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GcResourceKind.Descriptor instead.
+func (GcResourceKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+// GcRequest cleans up the loop devices, the mounts and the network resources left behind by the runs, which no longer exist.
+type GcRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dry_run only reports the resources, which would be cleaned up.
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *GcRequest) Reset() {
+	*x = GcRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_admin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GcRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GcRequest) ProtoMessage() {}
+
+func (x *GcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_admin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GcRequest.ProtoReflect.Descriptor instead.
+func (*GcRequest) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GcRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type GcResource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind GcResourceKind `protobuf:"varint,1,opt,name=kind,proto3,enum=fragma.core.v1.GcResourceKind" json:"kind,omitempty"`
+	// path is the mount point, the loop device, the address or the host network interface.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// run owned the resource, it is empty if the resource was not recorded in the journal.
+	Run string `protobuf:"bytes,3,opt,name=run,proto3" json:"run,omitempty"`
+	// backing_file is the file attached to the loop device.
+	BackingFile string `protobuf:"bytes,4,opt,name=backing_file,json=backingFile,proto3" json:"backing_file,omitempty"`
+	// error is set if the resource could not be cleaned up.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *GcResource) Reset() {
+	*x = GcResource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_admin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GcResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GcResource) ProtoMessage() {}
+
+func (x *GcResource) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_admin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GcResource.ProtoReflect.Descriptor instead.
+func (*GcResource) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GcResource) GetKind() GcResourceKind {
+	if x != nil {
+		return x.Kind
+	}
+	return GcResourceKind_GC_RESOURCE_KIND_MOUNT
+}
+
+func (x *GcResource) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GcResource) GetRun() string {
+	if x != nil {
+		return x.Run
+	}
+	return ""
+}
+
+func (x *GcResource) GetBackingFile() string {
+	if x != nil {
+		return x.BackingFile
+	}
+	return ""
+}
+
+func (x *GcResource) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Resources []*GcResource `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+}
+
+func (x *GcResponse) Reset() {
+	*x = GcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_admin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GcResponse) ProtoMessage() {}
+
+func (x *GcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_admin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GcResponse.ProtoReflect.Descriptor instead.
+func (*GcResponse) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GcResponse) GetResources() []*GcResource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+var File_api_fragma_core_v1_admin_proto protoreflect.FileDescriptor
+
+var file_api_fragma_core_v1_admin_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2f, 0x63, 0x6f, 0x72,
+	0x65, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0e, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x22, 0x24, 0x0a, 0x09, 0x47, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x0a, 0x47, 0x63, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x63, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4b,
+	0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x10, 0x0a,
+	0x03, 0x72, 0x75, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x12,
+	0x21, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x46, 0x0a, 0x0a, 0x47, 0x63, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x72, 0x61, 0x67,
+	0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x63, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x2a, 0x85, 0x01, 0x0a, 0x0e, 0x47, 0x63, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4b,
+	0x69, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x16, 0x47, 0x43, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x55, 0x52,
+	0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x00, 0x12,
+	0x19, 0x0a, 0x15, 0x47, 0x43, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x4b,
+	0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x4f, 0x4f, 0x50, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x47, 0x43,
+	0x5f, 0x52, 0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x41,
+	0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x47, 0x43, 0x5f, 0x52,
+	0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x49, 0x4e, 0x54,
+	0x45, 0x52, 0x46, 0x41, 0x43, 0x45, 0x10, 0x03, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6d, 0x62, 0x65, 0x64, 0x6e, 0x61, 0x72, 0x65,
+	0x6b, 0x2f, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x72, 0x61,
+	0x67, 0x6d, 0x61, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_fragma_core_v1_admin_proto_rawDescOnce sync.Once
+	file_api_fragma_core_v1_admin_proto_rawDescData = file_api_fragma_core_v1_admin_proto_rawDesc
+)
+
+func file_api_fragma_core_v1_admin_proto_rawDescGZIP() []byte {
+	file_api_fragma_core_v1_admin_proto_rawDescOnce.Do(func() {
+		file_api_fragma_core_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_fragma_core_v1_admin_proto_rawDescData)
+	})
+	return file_api_fragma_core_v1_admin_proto_rawDescData
+}
+
+var file_api_fragma_core_v1_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_fragma_core_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_fragma_core_v1_admin_proto_goTypes = []interface{}{
+	(GcResourceKind)(0), // 0: fragma.core.v1.GcResourceKind
+	(*GcRequest)(nil),   // 1: fragma.core.v1.GcRequest
+	(*GcResource)(nil),  // 2: fragma.core.v1.GcResource
+	(*GcResponse)(nil),  // 3: fragma.core.v1.GcResponse
+}
+var file_api_fragma_core_v1_admin_proto_depIdxs = []int32{
+	0, // 0: fragma.core.v1.GcResource.kind:type_name -> fragma.core.v1.GcResourceKind
+	2, // 1: fragma.core.v1.GcResponse.resources:type_name -> fragma.core.v1.GcResource
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_fragma_core_v1_admin_proto_init() }
+func file_api_fragma_core_v1_admin_proto_init() {
+	if File_api_fragma_core_v1_admin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_fragma_core_v1_admin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GcRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_fragma_core_v1_admin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GcResource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_fragma_core_v1_admin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_fragma_core_v1_admin_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_fragma_core_v1_admin_proto_goTypes,
+		DependencyIndexes: file_api_fragma_core_v1_admin_proto_depIdxs,
+		EnumInfos:         file_api_fragma_core_v1_admin_proto_enumTypes,
+		MessageInfos:      file_api_fragma_core_v1_admin_proto_msgTypes,
+	}.Build()
+	File_api_fragma_core_v1_admin_proto = out.File
+	file_api_fragma_core_v1_admin_proto_rawDesc = nil
+	file_api_fragma_core_v1_admin_proto_goTypes = nil
+	file_api_fragma_core_v1_admin_proto_depIdxs = nil
+}
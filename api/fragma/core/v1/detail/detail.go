@@ -16,12 +16,34 @@ var objects = map[string]model.ObjectDetail{
 		ProtoType:         (&core_v1.Application{}).ProtoReflect().Type(),
 		HighlightedFields: []string{"path", "name"},
 	},
+	"process": {
+		Version:           "v1",
+		SingularName:      "process",
+		PluralName:        "processes",
+		FullName:          "fragma.core.v1.Process",
+		ProtoType:         (&core_v1.Process{}).ProtoReflect().Type(),
+		HighlightedFields: []string{"name", "state", "pid", "restart_count", "address", "ports"},
+	},
+	"volume": {
+		Version:           "v1",
+		SingularName:      "volume",
+		PluralName:        "volumes",
+		FullName:          "fragma.core.v1.Volume",
+		ProtoType:         (&core_v1.Volume{}).ProtoReflect().Type(),
+		HighlightedFields: []string{"name", "path", "fs_type", "status.size", "status.free_space", "status.loop_devices", "status.processes"},
+	},
 }
 
 var aliases = map[string]string{
 	"app":          "application",
 	"apps":         "application",
 	"applications": "application",
+	"proc":         "process",
+	"procs":        "process",
+	"processes":    "process",
+	"vol":          "volume",
+	"vols":         "volume",
+	"volumes":      "volume",
 }
 
 type ApiDetail struct {
@@ -31,6 +53,10 @@ func (ApiDetail) Name() string {
 	return "fragma.core.v1"
 }
 
+func (ApiDetail) Objects() map[string]model.ObjectDetail {
+	return objects
+}
+
 func (d ApiDetail) GetObjectDetail(name string) (model.ObjectDetail, error) {
 	nameLC := strings.ToLower(name)
 
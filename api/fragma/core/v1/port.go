@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Format presents the mapping in the host->container/protocol notation.
+func (m *PortMapping) Format() string {
+	hostIP := m.HostIp
+	if len(hostIP) == 0 {
+		hostIP = "0.0.0.0"
+	}
+	protocol := strings.ToLower(strings.TrimPrefix(m.Protocol.String(), "PROTOCOL_"))
+	return fmt.Sprintf("%s->%d/%s", net.JoinHostPort(hostIP, strconv.Itoa(int(m.HostPort))), m.ContainerPort, protocol)
+}
+
+// splitHostIP separates the optional host ip from the ports, an ipv6 address has to be enclosed in brackets.
+func splitHostIP(address string) (string, string) {
+	if rest, ok := strings.CutPrefix(address, "["); ok {
+		if host, ports, ok := strings.Cut(rest, "]:"); ok {
+			return host, ports
+		}
+		return "", address
+	}
+	if strings.Count(address, ":") == 2 {
+		host, ports, _ := strings.Cut(address, ":")
+		return host, ports
+	}
+	return "", address
+}
+
+// ParsePortMappings reads comma separated mappings in the [host_ip:]host_port:container_port[/protocol] format,
+// an ipv6 host ip is enclosed in brackets, as in [::1]:8080:80.
+func ParsePortMappings(spec string) ([]*PortMapping, error) {
+	var result []*PortMapping
+	for _, item := range strings.Split(spec, ",") {
+		if len(item) == 0 {
+			continue
+		}
+
+		mapping := &PortMapping{}
+		address, protocol, ok := strings.Cut(item, "/")
+		if ok {
+			switch protocol {
+			case "tcp":
+				mapping.Protocol = Protocol_PROTOCOL_TCP
+			case "udp":
+				mapping.Protocol = Protocol_PROTOCOL_UDP
+			default:
+				return nil, fmt.Errorf("unknown protocol: %s", protocol)
+			}
+		}
+
+		hostIP, ports := splitHostIP(address)
+		if len(hostIP) != 0 && net.ParseIP(hostIP) == nil {
+			return nil, fmt.Errorf("invalid host ip: %s", hostIP)
+		}
+		mapping.HostIp = hostIP
+
+		parts := strings.Split(ports, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid port mapping: %s", item)
+		}
+
+		hostPort, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host port: %w", err)
+		}
+		containerPort, err := strconv.ParseUint(parts[1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid container port: %w", err)
+		}
+		mapping.HostPort = uint32(hostPort)
+		mapping.ContainerPort = uint32(containerPort)
+
+		result = append(result, mapping)
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	tests := []struct {
+		spec   string
+		result []string
+		valid  bool
+	}{
+		{spec: "8080:80", result: []string{"0.0.0.0:8080->80/tcp"}, valid: true},
+		{spec: "127.0.0.1:8080:80/udp,53:53", result: []string{"127.0.0.1:8080->80/udp", "0.0.0.0:53->53/tcp"}, valid: true},
+		{spec: "[::1]:8080:80", result: []string{"[::1]:8080->80/tcp"}, valid: true},
+		{spec: "[fd00::2]:53:53/udp", result: []string{"[fd00::2]:53->53/udp"}, valid: true},
+		{spec: "::1:8080:80"},
+		{spec: "[::1]8080:80"},
+		{spec: "[::1]:8080"},
+		{spec: "localhost:8080:80"},
+		{spec: "8080:80/sctp"},
+		{spec: "8080:70000"},
+	}
+	for _, test := range tests {
+		mappings, err := ParsePortMappings(test.spec)
+		if !test.valid {
+			require.Error(t, err, test.spec)
+			continue
+		}
+		require.NoError(t, err, test.spec)
+
+		var result []string
+		for _, mapping := range mappings {
+			result = append(result, mapping.Format())
+		}
+		require.Equal(t, test.result, result, test.spec)
+	}
+}
@@ -0,0 +1,607 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.4
+// source: api/fragma/core/v1/security.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SeccompAction int32
+
+const (
+	SeccompAction_SECCOMP_ACTION_ALLOW        SeccompAction = 0
+	SeccompAction_SECCOMP_ACTION_ERRNO        SeccompAction = 1
+	SeccompAction_SECCOMP_ACTION_KILL_PROCESS SeccompAction = 2
+	SeccompAction_SECCOMP_ACTION_KILL_THREAD  SeccompAction = 3
+	SeccompAction_SECCOMP_ACTION_TRAP         SeccompAction = 4
+	SeccompAction_SECCOMP_ACTION_LOG          SeccompAction = 5
+)
+
+// Enum value maps for SeccompAction.
+var (
+	SeccompAction_name = map[int32]string{
+		0: "SECCOMP_ACTION_ALLOW",
+		1: "SECCOMP_ACTION_ERRNO",
+		2: "SECCOMP_ACTION_KILL_PROCESS",
+		3: "SECCOMP_ACTION_KILL_THREAD",
+		4: "SECCOMP_ACTION_TRAP",
+		5: "SECCOMP_ACTION_LOG",
+	}
+	SeccompAction_value = map[string]int32{
+		"SECCOMP_ACTION_ALLOW":        0,
+		"SECCOMP_ACTION_ERRNO":        1,
+		"SECCOMP_ACTION_KILL_PROCESS": 2,
+		"SECCOMP_ACTION_KILL_THREAD":  3,
+		"SECCOMP_ACTION_TRAP":         4,
+		"SECCOMP_ACTION_LOG":          5,
+	}
+)
+
+func (x SeccompAction) Enum() *SeccompAction {
+	p := new(SeccompAction)
+	*p = x
+	return p
+}
+
+func (x SeccompAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeccompAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_fragma_core_v1_security_proto_enumTypes[0].Descriptor()
+}
+
+func (SeccompAction) Type() protoreflect.EnumType {
+	return &file_api_fragma_core_v1_security_proto_enumTypes[0]
+}
+
+func (x SeccompAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeccompAction.Descriptor instead.
+func (SeccompAction) EnumDescriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{0}
+}
+
+type SeccompOperator int32
+
+const (
+	SeccompOperator_SECCOMP_OPERATOR_EQUAL         SeccompOperator = 0
+	SeccompOperator_SECCOMP_OPERATOR_NOT_EQUAL     SeccompOperator = 1
+	SeccompOperator_SECCOMP_OPERATOR_LESS          SeccompOperator = 2
+	SeccompOperator_SECCOMP_OPERATOR_LESS_EQUAL    SeccompOperator = 3
+	SeccompOperator_SECCOMP_OPERATOR_GREATER       SeccompOperator = 4
+	SeccompOperator_SECCOMP_OPERATOR_GREATER_EQUAL SeccompOperator = 5
+	SeccompOperator_SECCOMP_OPERATOR_MASKED_EQUAL  SeccompOperator = 6
+)
+
+// Enum value maps for SeccompOperator.
+var (
+	SeccompOperator_name = map[int32]string{
+		0: "SECCOMP_OPERATOR_EQUAL",
+		1: "SECCOMP_OPERATOR_NOT_EQUAL",
+		2: "SECCOMP_OPERATOR_LESS",
+		3: "SECCOMP_OPERATOR_LESS_EQUAL",
+		4: "SECCOMP_OPERATOR_GREATER",
+		5: "SECCOMP_OPERATOR_GREATER_EQUAL",
+		6: "SECCOMP_OPERATOR_MASKED_EQUAL",
+	}
+	SeccompOperator_value = map[string]int32{
+		"SECCOMP_OPERATOR_EQUAL":         0,
+		"SECCOMP_OPERATOR_NOT_EQUAL":     1,
+		"SECCOMP_OPERATOR_LESS":          2,
+		"SECCOMP_OPERATOR_LESS_EQUAL":    3,
+		"SECCOMP_OPERATOR_GREATER":       4,
+		"SECCOMP_OPERATOR_GREATER_EQUAL": 5,
+		"SECCOMP_OPERATOR_MASKED_EQUAL":  6,
+	}
+)
+
+func (x SeccompOperator) Enum() *SeccompOperator {
+	p := new(SeccompOperator)
+	*p = x
+	return p
+}
+
+func (x SeccompOperator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeccompOperator) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_fragma_core_v1_security_proto_enumTypes[1].Descriptor()
+}
+
+func (SeccompOperator) Type() protoreflect.EnumType {
+	return &file_api_fragma_core_v1_security_proto_enumTypes[1]
+}
+
+func (x SeccompOperator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeccompOperator.Descriptor instead.
+func (SeccompOperator) EnumDescriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{1}
+}
+
+type SeccompArg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index    uint32          `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Op       SeccompOperator `protobuf:"varint,2,opt,name=op,proto3,enum=fragma.core.v1.SeccompOperator" json:"op,omitempty"`
+	Value    uint64          `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	ValueTwo uint64          `protobuf:"varint,4,opt,name=value_two,json=valueTwo,proto3" json:"value_two,omitempty"`
+}
+
+func (x *SeccompArg) Reset() {
+	*x = SeccompArg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_security_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeccompArg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeccompArg) ProtoMessage() {}
+
+func (x *SeccompArg) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_security_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeccompArg.ProtoReflect.Descriptor instead.
+func (*SeccompArg) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SeccompArg) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SeccompArg) GetOp() SeccompOperator {
+	if x != nil {
+		return x.Op
+	}
+	return SeccompOperator_SECCOMP_OPERATOR_EQUAL
+}
+
+func (x *SeccompArg) GetValue() uint64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SeccompArg) GetValueTwo() uint64 {
+	if x != nil {
+		return x.ValueTwo
+	}
+	return 0
+}
+
+type SeccompRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Names  []string      `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	Action SeccompAction `protobuf:"varint,2,opt,name=action,proto3,enum=fragma.core.v1.SeccompAction" json:"action,omitempty"`
+	Errno  uint32        `protobuf:"varint,3,opt,name=errno,proto3" json:"errno,omitempty"`
+	Args   []*SeccompArg `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+}
+
+func (x *SeccompRule) Reset() {
+	*x = SeccompRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_security_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeccompRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeccompRule) ProtoMessage() {}
+
+func (x *SeccompRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_security_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeccompRule.ProtoReflect.Descriptor instead.
+func (*SeccompRule) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SeccompRule) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *SeccompRule) GetAction() SeccompAction {
+	if x != nil {
+		return x.Action
+	}
+	return SeccompAction_SECCOMP_ACTION_ALLOW
+}
+
+func (x *SeccompRule) GetErrno() uint32 {
+	if x != nil {
+		return x.Errno
+	}
+	return 0
+}
+
+func (x *SeccompRule) GetArgs() []*SeccompArg {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+type SeccompProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DefaultAction SeccompAction  `protobuf:"varint,1,opt,name=default_action,json=defaultAction,proto3,enum=fragma.core.v1.SeccompAction" json:"default_action,omitempty"`
+	DefaultErrno  uint32         `protobuf:"varint,2,opt,name=default_errno,json=defaultErrno,proto3" json:"default_errno,omitempty"`
+	Rules         []*SeccompRule `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *SeccompProfile) Reset() {
+	*x = SeccompProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_security_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeccompProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeccompProfile) ProtoMessage() {}
+
+func (x *SeccompProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_security_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeccompProfile.ProtoReflect.Descriptor instead.
+func (*SeccompProfile) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SeccompProfile) GetDefaultAction() SeccompAction {
+	if x != nil {
+		return x.DefaultAction
+	}
+	return SeccompAction_SECCOMP_ACTION_ALLOW
+}
+
+func (x *SeccompProfile) GetDefaultErrno() uint32 {
+	if x != nil {
+		return x.DefaultErrno
+	}
+	return 0
+}
+
+func (x *SeccompProfile) GetRules() []*SeccompRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// Capabilities are applied right before the application is executed. After execve a user other than root
+// keeps only the ambient set. Root is granted the whole bounding set, unless the effective or the permitted set
+// is narrower, then it keeps the effective set along with the ambient one.
+type Capabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bounding  []string `protobuf:"bytes,1,rep,name=bounding,proto3" json:"bounding,omitempty"`
+	Effective []string `protobuf:"bytes,2,rep,name=effective,proto3" json:"effective,omitempty"`
+	Permitted []string `protobuf:"bytes,3,rep,name=permitted,proto3" json:"permitted,omitempty"`
+	Ambient   []string `protobuf:"bytes,4,rep,name=ambient,proto3" json:"ambient,omitempty"`
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_fragma_core_v1_security_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fragma_core_v1_security_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_api_fragma_core_v1_security_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Capabilities) GetBounding() []string {
+	if x != nil {
+		return x.Bounding
+	}
+	return nil
+}
+
+func (x *Capabilities) GetEffective() []string {
+	if x != nil {
+		return x.Effective
+	}
+	return nil
+}
+
+func (x *Capabilities) GetPermitted() []string {
+	if x != nil {
+		return x.Permitted
+	}
+	return nil
+}
+
+func (x *Capabilities) GetAmbient() []string {
+	if x != nil {
+		return x.Ambient
+	}
+	return nil
+}
+
+var File_api_fragma_core_v1_security_proto protoreflect.FileDescriptor
+
+var file_api_fragma_core_v1_security_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2f, 0x63, 0x6f, 0x72,
+	0x65, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x22, 0x86, 0x01, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41,
+	0x72, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2f, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x74, 0x77, 0x6f, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x77, 0x6f, 0x22, 0xa0, 0x01, 0x0a,
+	0x0b, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x12, 0x35, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6e, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6e, 0x6f, 0x12,
+	0x2e, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41, 0x72, 0x67, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x22,
+	0xae, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x66, 0x72, 0x61,
+	0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x63,
+	0x6f, 0x6d, 0x70, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x45, 0x72, 0x72, 0x6e, 0x6f, 0x12, 0x31, 0x0a,
+	0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x66,
+	0x72, 0x61, 0x67, 0x6d, 0x61, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x63, 0x63, 0x6f, 0x6d, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x22, 0x80, 0x01, 0x0a, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a,
+	0x09, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x09, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6d, 0x62,
+	0x69, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6d, 0x62, 0x69,
+	0x65, 0x6e, 0x74, 0x2a, 0xb5, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50,
+	0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x10, 0x00, 0x12,
+	0x18, 0x0a, 0x14, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4e, 0x4f, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x45, 0x43,
+	0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4b, 0x49, 0x4c, 0x4c,
+	0x5f, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x45,
+	0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4b, 0x49, 0x4c,
+	0x4c, 0x5f, 0x54, 0x48, 0x52, 0x45, 0x41, 0x44, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x45,
+	0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x52, 0x41,
+	0x50, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x41,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4c, 0x4f, 0x47, 0x10, 0x05, 0x2a, 0xee, 0x01, 0x0a, 0x0f,
+	0x53, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x1a, 0x0a, 0x16, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41,
+	0x54, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x53,
+	0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x53,
+	0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f,
+	0x4c, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d,
+	0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x4c, 0x45, 0x53, 0x53, 0x5f,
+	0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x03, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x45, 0x43, 0x43, 0x4f,
+	0x4d, 0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x47, 0x52, 0x45, 0x41,
+	0x54, 0x45, 0x52, 0x10, 0x04, 0x12, 0x22, 0x0a, 0x1e, 0x53, 0x45, 0x43, 0x43, 0x4f, 0x4d, 0x50,
+	0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45,
+	0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x05, 0x12, 0x21, 0x0a, 0x1d, 0x53, 0x45, 0x43,
+	0x43, 0x4f, 0x4d, 0x50, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x5f, 0x4d, 0x41,
+	0x53, 0x4b, 0x45, 0x44, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x06, 0x42, 0x31, 0x5a, 0x2f,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6d, 0x62, 0x65, 0x64,
+	0x6e, 0x61, 0x72, 0x65, 0x6b, 0x2f, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x61, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_fragma_core_v1_security_proto_rawDescOnce sync.Once
+	file_api_fragma_core_v1_security_proto_rawDescData = file_api_fragma_core_v1_security_proto_rawDesc
+)
+
+func file_api_fragma_core_v1_security_proto_rawDescGZIP() []byte {
+	file_api_fragma_core_v1_security_proto_rawDescOnce.Do(func() {
+		file_api_fragma_core_v1_security_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_fragma_core_v1_security_proto_rawDescData)
+	})
+	return file_api_fragma_core_v1_security_proto_rawDescData
+}
+
+var file_api_fragma_core_v1_security_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_fragma_core_v1_security_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_api_fragma_core_v1_security_proto_goTypes = []interface{}{
+	(SeccompAction)(0),     // 0: fragma.core.v1.SeccompAction
+	(SeccompOperator)(0),   // 1: fragma.core.v1.SeccompOperator
+	(*SeccompArg)(nil),     // 2: fragma.core.v1.SeccompArg
+	(*SeccompRule)(nil),    // 3: fragma.core.v1.SeccompRule
+	(*SeccompProfile)(nil), // 4: fragma.core.v1.SeccompProfile
+	(*Capabilities)(nil),   // 5: fragma.core.v1.Capabilities
+}
+var file_api_fragma_core_v1_security_proto_depIdxs = []int32{
+	1, // 0: fragma.core.v1.SeccompArg.op:type_name -> fragma.core.v1.SeccompOperator
+	0, // 1: fragma.core.v1.SeccompRule.action:type_name -> fragma.core.v1.SeccompAction
+	2, // 2: fragma.core.v1.SeccompRule.args:type_name -> fragma.core.v1.SeccompArg
+	0, // 3: fragma.core.v1.SeccompProfile.default_action:type_name -> fragma.core.v1.SeccompAction
+	3, // 4: fragma.core.v1.SeccompProfile.rules:type_name -> fragma.core.v1.SeccompRule
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_fragma_core_v1_security_proto_init() }
+func file_api_fragma_core_v1_security_proto_init() {
+	if File_api_fragma_core_v1_security_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_fragma_core_v1_security_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeccompArg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_fragma_core_v1_security_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeccompRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_fragma_core_v1_security_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeccompProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_fragma_core_v1_security_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Capabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_fragma_core_v1_security_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_fragma_core_v1_security_proto_goTypes,
+		DependencyIndexes: file_api_fragma_core_v1_security_proto_depIdxs,
+		EnumInfos:         file_api_fragma_core_v1_security_proto_enumTypes,
+		MessageInfos:      file_api_fragma_core_v1_security_proto_msgTypes,
+	}.Build()
+	File_api_fragma_core_v1_security_proto = out.File
+	file_api_fragma_core_v1_security_proto_rawDesc = nil
+	file_api_fragma_core_v1_security_proto_goTypes = nil
+	file_api_fragma_core_v1_security_proto_depIdxs = nil
+}
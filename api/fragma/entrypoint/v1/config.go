@@ -0,0 +1,46 @@
+package v1
+
+import "github.com/mmbednarek/fragma/pkg/linux"
+
+// ConfigVersion is the version of the ContainerConfig, the entrypoint refuses other versions.
+const ConfigVersion = 1
+
+var mountKinds = map[MountKind]linux.MountKind{
+	MountKind_MOUNT_KIND_BIND:   linux.MountBind,
+	MountKind_MOUNT_KIND_TMPFS:  linux.MountTmpfs,
+	MountKind_MOUNT_KIND_PROC:   linux.MountProc,
+	MountKind_MOUNT_KIND_SYSFS:  linux.MountSysfs,
+	MountKind_MOUNT_KIND_DEVPTS: linux.MountDevpts,
+	MountKind_MOUNT_KIND_MQUEUE: linux.MountMqueue,
+}
+
+func NewMount(spec linux.MountSpec) *Mount {
+	mount := &Mount{
+		Source:       spec.Source,
+		Destination:  spec.Destination,
+		ReadOnly:     spec.ReadOnly,
+		Recursive:    spec.Recursive,
+		Size:         spec.Size,
+		Mode:         spec.Mode,
+		AllowDevices: spec.AllowDevices,
+	}
+	for kind, linuxKind := range mountKinds {
+		if linuxKind == spec.Kind {
+			mount.Kind = kind
+		}
+	}
+	return mount
+}
+
+func (m *Mount) Spec() linux.MountSpec {
+	return linux.MountSpec{
+		Kind:         mountKinds[m.Kind],
+		Source:       m.Source,
+		Destination:  m.Destination,
+		ReadOnly:     m.ReadOnly,
+		Recursive:    m.Recursive,
+		Size:         m.Size,
+		Mode:         m.Mode,
+		AllowDevices: m.AllowDevices,
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func (f *Frontend) mountAdmin(root *cobra.Command) {
+	adminCmd := &cobra.Command{
+		Use:   "admin",
+		Short: "maintenance of the node",
+	}
+
+	gcCmd := &cobra.Command{
+		Use:   "gc [flags]",
+		Short: "release the loop devices, the mounts and the network resources left behind by the runs, which no longer exist",
+		Args:  cobra.NoArgs,
+		Run:   f.HandleGc,
+	}
+	gcCmd.Flags().Bool("dry-run", false, "only show what would be released")
+	adminCmd.AddCommand(gcCmd)
+
+	root.AddCommand(adminCmd)
+}
+
+func gcResourceKind(kind core.GcResourceKind) string {
+	return strings.ToLower(strings.TrimPrefix(kind.String(), "GC_RESOURCE_KIND_"))
+}
+
+func (f *Frontend) HandleGc(cmd *cobra.Command, args []string) {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		die("%s", err)
+	}
+
+	response, err := f.Client.CollectGarbage(&core.GcRequest{DryRun: dryRun})
+	if err != nil {
+		die("could not collect garbage: %s", err)
+	}
+	if len(response.Resources) == 0 {
+		fmt.Println("nothing to release")
+		return
+	}
+
+	table := util.NewTable()
+	failed := false
+	for _, resource := range response.Resources {
+		status := "released"
+		switch {
+		case dryRun:
+			status = "would release"
+		case len(resource.Error) != 0:
+			status = resource.Error
+			failed = true
+		}
+		table.Add("kind", gcResourceKind(resource.Kind))
+		table.Add("path", resource.Path)
+		table.Add("run", resource.Run)
+		table.Add("backing file", resource.BackingFile)
+		table.Add("status", status)
+	}
+	table.Print(os.Stdout)
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/spf13/cobra"
+)
+
+const defaultDetachKeys = "ctrl-p,ctrl-q"
+
+var errDetached = errors.New("detached")
+
+func (f *Frontend) mountAttach(root *cobra.Command) {
+	attachCmd := &cobra.Command{
+		Use:   "attach [flags] name",
+		Short: "connect to the terminal of a running process",
+		Args:  cobra.ExactArgs(1),
+		Run:   f.HandleAttach,
+	}
+	attachCmd.Flags().String("detach-keys", defaultDetachKeys, "key sequence, which detaches from the terminal and leaves the process running")
+	root.AddCommand(attachCmd)
+}
+
+func (f *Frontend) HandleAttach(cmd *cobra.Command, args []string) {
+	detachKeys, err := cmd.Flags().GetString("detach-keys")
+	if err != nil {
+		die("%s", err)
+	}
+	os.Exit(f.attach(args[0], detachKeys))
+}
+
+// attach forwards the stdio to the terminal of the process until it exits or the detach keys are pressed,
+// it returns the exit code of the process.
+func (f *Frontend) attach(name string, detachKeys string) int {
+	keys, err := parseDetachKeys(detachKeys)
+	if err != nil {
+		die("invalid --detach-keys: %s", err)
+	}
+
+	request := &core.AttachRequest{}
+	tty := linux.Isatty(os.Stdin)
+	var hostAttr linux.Termios
+	if tty {
+		hostAttr, err = linux.Attr(os.Stdin)
+		if err != nil {
+			die("could not get terminal attributes: %s", err)
+		}
+		if err := hostAttr.Winsz(os.Stdin); err == nil {
+			request.Rows = uint32(hostAttr.Wz.WsRow)
+			request.Cols = uint32(hostAttr.Wz.WsCol)
+		}
+	}
+
+	conn, err := f.Client.Attach(name, request)
+	if err != nil {
+		die("could not attach: %s", err)
+	}
+	defer conn.Close()
+
+	if tty {
+		raw := hostAttr
+		raw.Raw()
+		if err := raw.Set(os.Stdin); err != nil {
+			die("could not set terminal attributes: %s", err)
+		}
+		go forwardResize(conn)
+	}
+
+	var stdin io.Reader = os.Stdin
+	if len(keys) > 0 {
+		stdin = &detachReader{reader: os.Stdin, keys: keys}
+	}
+	code, detached := streamExec(conn, stdin)
+	if tty {
+		_ = hostAttr.Set(os.Stdin)
+	}
+	if detached {
+		_, _ = fmt.Fprintf(os.Stderr, "\ndetached from %s\n", name)
+		return 0
+	}
+	return code
+}
+
+// parseDetachKeys parses a comma separated list of keys, a key is either a single character or ctrl-<character>.
+func parseDetachKeys(value string) ([]byte, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var keys []byte
+	for _, key := range strings.Split(value, ",") {
+		if len(key) == 1 {
+			keys = append(keys, key[0])
+			continue
+		}
+
+		character, ok := strings.CutPrefix(key, "ctrl-")
+		if !ok || len(character) != 1 {
+			return nil, fmt.Errorf("unknown key: %s", key)
+		}
+		switch c := character[0]; {
+		case c >= 'a' && c <= 'z':
+			keys = append(keys, c-'a'+1)
+		case c == '@', c == '[', c == '\\', c == ']', c == '^', c == '_':
+			keys = append(keys, c-'@')
+		default:
+			return nil, fmt.Errorf("unknown key: %s", key)
+		}
+	}
+	return keys, nil
+}
+
+// detachReader passes the input through and fails with errDetached, once the input contains the detach keys.
+// A started sequence is held back until it either completes or turns out to be regular input.
+type detachReader struct {
+	reader   io.Reader
+	keys     []byte
+	matched  int
+	pending  []byte
+	err      error
+	detached bool
+}
+
+func (d *detachReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.detached {
+			return 0, errDetached
+		}
+		if d.err != nil {
+			return 0, d.err
+		}
+
+		buff := make([]byte, len(p))
+		n, err := d.reader.Read(buff)
+		d.err = err
+		for _, b := range buff[:n] {
+			if b != d.keys[d.matched] {
+				d.pending = append(d.pending, d.keys[:d.matched]...)
+				d.matched = 0
+				if b != d.keys[0] {
+					d.pending = append(d.pending, b)
+					continue
+				}
+			}
+
+			d.matched++
+			if d.matched == len(d.keys) {
+				d.detached = true
+				break
+			}
+		}
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDetachKeys(t *testing.T) {
+	keys, err := parseDetachKeys("ctrl-p,ctrl-q")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x10, 0x11}, keys)
+
+	keys, err = parseDetachKeys("ctrl-[,x")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1b, 'x'}, keys)
+
+	keys, err = parseDetachKeys("")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	_, err = parseDetachKeys("ctrl-1")
+	require.Error(t, err)
+	_, err = parseDetachKeys("alt-p")
+	require.Error(t, err)
+}
+
+func TestDetachReader(t *testing.T) {
+	keys := []byte{0x10, 0x11}
+
+	// A started sequence, which does not complete, is regular input.
+	input := []byte{'a', 0x10, 'b', 0x10, 0x10, 0x11, 'c'}
+	reader := &detachReader{reader: iotest.OneByteReader(bytes.NewReader(input)), keys: keys}
+	output, err := io.ReadAll(reader)
+	require.True(t, errors.Is(err, errDetached))
+	require.Equal(t, []byte{'a', 0x10, 'b', 0x10}, output)
+
+	reader = &detachReader{reader: bytes.NewReader([]byte("hello")), keys: keys}
+	output, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), output)
+}
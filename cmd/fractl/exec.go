@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/stream"
+	"github.com/spf13/cobra"
+)
+
+func (f *Frontend) mountExec(root *cobra.Command) {
+	execCmd := &cobra.Command{
+		Use:   "exec [flags] name -- command [args...]",
+		Short: "run a command inside a running process",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   f.HandleExec,
+	}
+	flags := execCmd.Flags()
+	flags.BoolP("interactive", "i", false, "forward stdin to the command")
+	flags.BoolP("tty", "t", false, "allocate a terminal for the command")
+	flags.StringSliceP("env", "e", nil, "environment variables in the KEY=VALUE format")
+	flags.StringP("workdir", "w", "", "working directory of the command")
+	root.AddCommand(execCmd)
+}
+
+func (f *Frontend) HandleExec(cmd *cobra.Command, args []string) {
+	flags := NewFlagErrChain(cmd.Flags())
+	interactive := flags.GetBool("interactive")
+	tty := flags.GetBool("tty")
+	env := flags.GetStringSlice("env")
+	workdir := flags.GetString("workdir")
+	if err := flags.Verify(); err != nil {
+		die("%s", err)
+	}
+
+	request := &core.ExecRequest{
+		Arguments:   args[1:],
+		Environment: map[string]string{},
+		Tty:         tty,
+		Stdin:       interactive,
+	}
+	if workdir != nil {
+		request.WorkingDir = *workdir
+	}
+	for _, variable := range env {
+		key, value, ok := strings.Cut(variable, "=")
+		if !ok {
+			die("invalid --env: %s", variable)
+		}
+		request.Environment[key] = value
+	}
+
+	var hostAttr linux.Termios
+	if tty {
+		var err error
+		hostAttr, err = linux.Attr(os.Stdin)
+		if err != nil {
+			die("the --tty flag requires a terminal: %s", err)
+		}
+		if err := hostAttr.Winsz(os.Stdin); err == nil {
+			request.Rows = uint32(hostAttr.Wz.WsRow)
+			request.Cols = uint32(hostAttr.Wz.WsCol)
+		}
+	}
+
+	conn, err := f.Client.Exec(args[0], request)
+	if err != nil {
+		die("could not exec: %s", err)
+	}
+	defer conn.Close()
+
+	if tty {
+		raw := hostAttr
+		raw.Raw()
+		if err := raw.Set(os.Stdin); err != nil {
+			die("could not set terminal attributes: %s", err)
+		}
+		go forwardResize(conn)
+	}
+
+	var stdin io.Reader
+	if interactive {
+		stdin = os.Stdin
+	}
+	code, _ := streamExec(conn, stdin)
+	if tty {
+		_ = hostAttr.Set(os.Stdin)
+	}
+	os.Exit(code)
+}
+
+func forwardResize(conn *stream.Conn) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	for range signals {
+		var attr linux.Termios
+		if err := attr.Winsz(os.Stdin); err != nil {
+			continue
+		}
+		if err := conn.WriteFrame(stream.FrameResize, stream.EncodeResize(attr.Wz.WsRow, attr.Wz.WsCol)); err != nil {
+			return
+		}
+	}
+}
+
+// streamExec forwards the stdio until the process exits and returns its exit code. If stdin is nil, the input
+// is not forwarded. The stream ends early if reading stdin fails with errDetached.
+func streamExec(conn *stream.Conn, stdin io.Reader) (int, bool) {
+	detached := make(chan struct{})
+	if stdin != nil {
+		go func() {
+			_, err := io.Copy(conn.Writer(stream.FrameStdin), stdin)
+			if errors.Is(err, errDetached) {
+				close(detached)
+				_ = conn.Close()
+				return
+			}
+			if err != nil {
+				return
+			}
+			_ = conn.WriteFrame(stream.FrameCloseStdin, nil)
+		}()
+	}
+
+	for {
+		frame, err := conn.ReadFrame()
+		if err != nil {
+			select {
+			case <-detached:
+				return 0, true
+			default:
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "connection closed: %s\n", err)
+			return 1, false
+		}
+
+		switch frame.Type {
+		case stream.FrameStdout:
+			_, _ = os.Stdout.Write(frame.Payload)
+		case stream.FrameStderr:
+			_, _ = os.Stderr.Write(frame.Payload)
+		case stream.FrameError:
+			_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", frame.Payload)
+			return 1, false
+		case stream.FrameExit:
+			code, err := stream.DecodeExit(frame.Payload)
+			if err != nil {
+				return 1, false
+			}
+			return int(code), false
+		}
+	}
+}
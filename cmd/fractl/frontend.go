@@ -8,9 +8,12 @@ import (
 	"strings"
 	"time"
 
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
 	"github.com/mmbednarek/fragma/model"
 	"github.com/mmbednarek/fragma/model/repo"
+	"github.com/mmbednarek/fragma/pkg/logfile"
 	"github.com/mmbednarek/fragma/pkg/protoutil"
+	"github.com/mmbednarek/fragma/pkg/stream"
 	"github.com/mmbednarek/fragma/pkg/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -22,6 +25,13 @@ type Client interface {
 	GetObject(api string, typeName string, name string) (model.Object, error)
 	WriteObject(obj model.Object) error
 	DeleteObject(apiName string, typeName string, name string) error
+	Run(request *core.RunRequest) (*core.RunResponse, error)
+	Exec(name string, request *core.ExecRequest) (*stream.Conn, error)
+	Attach(name string, request *core.AttachRequest) (*stream.Conn, error)
+	Stop(name string, request *core.StopRequest) (*core.Process, error)
+	Signal(name string, request *core.SignalRequest) error
+	Logs(name string, options logfile.ReadOptions, fn func(logfile.Record) error) error
+	CollectGarbage(request *core.GcRequest) (*core.GcResponse, error)
 }
 
 type Frontend struct {
@@ -59,6 +69,13 @@ func (f *Frontend) Mount() *cobra.Command {
 	}
 	root.AddCommand(deleteCmd)
 
+	f.mountRun(root)
+	f.mountExec(root)
+	f.mountAttach(root)
+	f.mountStop(root)
+	f.mountLogs(root)
+	f.mountAdmin(root)
+
 	return root
 }
 
@@ -111,7 +128,7 @@ func (f *Frontend) HandleGet(cmd *cobra.Command, args []string) {
 
 	for _, obj := range objs {
 		for _, field := range typeDep.HighlightedFields {
-			table.Add(field, protoutil.ExtractValueByFieldName[string](obj.Spec, field))
+			table.Add(field, protoutil.FormatValueByFieldName(obj.Spec, field))
 		}
 	}
 
@@ -156,12 +173,13 @@ func (f *Frontend) HandleDelete(cmd *cobra.Command, args []string) {
 	api, typeName := getApiAndTypeName(args[0])
 
 	if err := f.Client.DeleteObject(api, typeName, args[1]); err != nil {
-		die("f.Client.WriteObject: %s", err)
+		die("could not delete object: %s", err)
 	}
 }
 
+// die prints the message followed by a newline and exits.
 func die(format string, args ...any) {
-	_, _ = fmt.Fprintf(os.Stderr, format, args...)
+	_, _ = fmt.Fprintf(os.Stderr, format+"\n", args...)
 	os.Exit(1)
 }
 
@@ -210,6 +228,15 @@ func (c *FlagErrChain) GetStringSlice(name string) []string {
 	return value
 }
 
+func (c *FlagErrChain) GetStringArray(name string) []string {
+	value, err := c.Flags.GetStringArray(name)
+	if err != nil {
+		c.ErrorMap[name] = err
+		return nil
+	}
+	return value
+}
+
 func (c *FlagErrChain) GetBool(name string) bool {
 	value, err := c.Flags.GetBool(name)
 	if err != nil {
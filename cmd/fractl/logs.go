@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mmbednarek/fragma/pkg/logfile"
+	"github.com/spf13/cobra"
+)
+
+func (f *Frontend) mountLogs(root *cobra.Command) {
+	logsCmd := &cobra.Command{
+		Use:   "logs [flags] name",
+		Short: "print the output of a process",
+		Args:  cobra.ExactArgs(1),
+		Run:   f.HandleLogs,
+	}
+	logsCmd.Flags().BoolP("follow", "f", false, "keep printing the output until the process exits")
+	logsCmd.Flags().String("since", "", "only print the output since a time (RFC3339) or a duration ago, for example 10m")
+	logsCmd.Flags().IntP("tail", "n", -1, "number of the last lines to print, all of them if negative")
+	logsCmd.Flags().BoolP("timestamps", "t", false, "prefix the lines with their timestamps")
+	root.AddCommand(logsCmd)
+}
+
+// parseSince accepts either a timestamp or a duration relative to now.
+func parseSince(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	since, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: expected a duration or an RFC3339 time", value)
+	}
+	return since, nil
+}
+
+func (f *Frontend) HandleLogs(cmd *cobra.Command, args []string) {
+	flags := NewFlagErrChain(cmd.Flags())
+	follow := flags.GetBool("follow")
+	since := flags.GetString("since")
+	tail := flags.GetInt("tail")
+	timestamps := flags.GetBool("timestamps")
+	if err := flags.Verify(); err != nil {
+		die("%s", err)
+	}
+
+	options := logfile.ReadOptions{
+		Tail:   -1,
+		Follow: follow,
+	}
+	if since != nil {
+		parsed, err := parseSince(*since)
+		if err != nil {
+			die("%s", err)
+		}
+		options.Since = parsed
+	}
+	if tail != nil {
+		options.Tail = *tail
+	}
+
+	err := f.Client.Logs(args[0], options, func(record logfile.Record) error {
+		var out io.Writer = os.Stdout
+		if record.Stream == logfile.StreamStderr {
+			out = os.Stderr
+		}
+		if timestamps {
+			_, err := fmt.Fprintf(out, "%s %s", record.Time.Format(time.RFC3339Nano), record.Line)
+			return err
+		}
+		_, err := out.Write(record.Line)
+		return err
+	})
+	if err != nil {
+		die("could not read logs: %s", err)
+	}
+}
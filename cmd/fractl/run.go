@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func (f *Frontend) mountRun(root *cobra.Command) {
+	runCmd := &cobra.Command{
+		Use:   "run [flags] [--] [path] [args...]",
+		Short: "start an application in the background",
+		Args:  cobra.ArbitraryArgs,
+		Run:   f.HandleRun,
+	}
+	flags := runCmd.Flags()
+	flags.String("name", "", "name of the process, generated if not set")
+	flags.String("volume", "", "path of the volume image used as the root")
+	flags.String("app", "", "name of an application object to run instead of the path")
+	flags.StringSlice("publish", nil, "ports to publish in the [host_ip:]host_port:container_port[/protocol] format, an ipv6 host_ip is enclosed in brackets")
+	flags.StringSlice("env", nil, "environment variables in the KEY=VALUE format")
+	flags.String("volume-fs", "", "filesystem of the volume, detected from the image by default")
+	flags.StringSlice("volume-opt", nil, "mount options passed to the filesystem of the volume")
+	flags.Bool("read-only-volume", false, "mount the volume read-only, the changes are written to an overlay")
+	flags.Bool("overlay", false, "keep the volume unmodified and write the changes to an overlay")
+	flags.Bool("host-network", false, "share the network namespace of the host")
+	flags.String("stop-signal", "", "signal sent when the process is stopped, SIGTERM by default")
+	flags.String("stop-timeout", "", "grace period before a stopped process is killed, 10s by default")
+	flags.String("restart", "never", "restart policy of the process (never, on-failure or always)")
+	flags.Int("max-retries", 0, "limit of the consecutive restarts, unlimited by default")
+	flags.StringArray("process", nil, "additional process supervised next to the application in the name=path [args...] format, can be repeated")
+	flags.StringSlice("restart-process", nil, "names of the additional processes restarted after they exit")
+	flags.BoolP("tty", "t", false, "allocate a terminal, which can be attached to")
+	flags.BoolP("interactive", "i", false, "attach to the terminal once the process starts, requires --tty")
+	flags.String("detach-keys", defaultDetachKeys, "key sequence, which detaches from the terminal and leaves the process running")
+	flags.StringP("user", "u", "", "user of the application in the uid[:gid] format, root by default")
+	flags.String("hostname", "", "hostname of the container, fragma-pod by default")
+	flags.StringP("workdir", "w", "", "working directory of the application, /root by default")
+	flags.StringArray("ulimit", nil, "resource limit in the name=soft[:hard] format, can be repeated")
+	flags.StringArray("device", nil, "device node in the path:c|b:major:minor[:mode[:uid:gid]] format, can be repeated")
+	flags.StringArray("masked-path", nil, "path hidden from the container, replaces the default masked and read-only paths, can be repeated")
+	flags.StringArray("readonly-path", nil, "path remounted read-only, replaces the default masked and read-only paths, can be repeated")
+	flags.Bool("unrestricted-paths", false, "leave the /proc and /sys paths, which are masked or read-only by default, accessible")
+	root.AddCommand(runCmd)
+}
+
+func (f *Frontend) HandleRun(cmd *cobra.Command, args []string) {
+	flags := NewFlagErrChain(cmd.Flags())
+	name := flags.GetString("name")
+	volume := flags.GetString("volume")
+	appName := flags.GetString("app")
+	publish := flags.GetStringSlice("publish")
+	env := flags.GetStringSlice("env")
+	volumeFs := flags.GetString("volume-fs")
+	volumeOptions := flags.GetStringSlice("volume-opt")
+	readOnlyVolume := flags.GetBool("read-only-volume")
+	overlay := flags.GetBool("overlay")
+	hostNetwork := flags.GetBool("host-network")
+	stopSignal := flags.GetString("stop-signal")
+	stopTimeout := flags.GetDuration("stop-timeout")
+	restartPolicy := flags.GetString("restart")
+	maxRetries := flags.GetInt("max-retries")
+	processSpecs := flags.GetStringArray("process")
+	restartProcesses := flags.GetStringSlice("restart-process")
+	tty := flags.GetBool("tty")
+	interactive := flags.GetBool("interactive")
+	detachKeys := flags.GetString("detach-keys")
+	user := flags.GetString("user")
+	hostname := flags.GetString("hostname")
+	workdir := flags.GetString("workdir")
+	ulimits := flags.GetStringArray("ulimit")
+	deviceSpecs := flags.GetStringArray("device")
+	maskedPaths := flags.GetStringArray("masked-path")
+	readonlyPaths := flags.GetStringArray("readonly-path")
+	unrestrictedPaths := flags.GetBool("unrestricted-paths")
+	if err := flags.Verify(); err != nil {
+		die("%s", err)
+	}
+
+	if interactive && !tty {
+		die("the --interactive flag requires --tty")
+	}
+	keys := defaultDetachKeys
+	if detachKeys != nil {
+		keys = *detachKeys
+	}
+	if _, err := parseDetachKeys(keys); err != nil {
+		die("invalid --detach-keys: %s", err)
+	}
+
+	if volume == nil {
+		die("the --volume flag is required")
+	}
+
+	var application *core.Application
+	if appName != nil {
+		obj, err := f.Client.GetObject("fragma.core.v1", "application", *appName)
+		if err != nil {
+			die("could not get application: %s", err)
+		}
+		app, ok := obj.Spec.Message.(*core.Application)
+		if !ok {
+			die("invalid application object")
+		}
+		application = app
+		args = append([]string{app.Path}, args...)
+	} else {
+		if len(args) == 0 {
+			die("either the --app flag or the path of the application is required")
+		}
+		application = &core.Application{Name: args[0], Path: args[0]}
+	}
+
+	ports := make([]*core.PortMapping, 0, len(publish))
+	for _, spec := range publish {
+		mappings, err := core.ParsePortMappings(spec)
+		if err != nil {
+			die("invalid --publish: %s", err)
+		}
+		ports = append(ports, mappings...)
+	}
+
+	processes, err := parseProcesses(processSpecs, restartProcesses)
+	if err != nil {
+		die("%s", err)
+	}
+
+	rlimits := make([]*core.Rlimit, 0, len(ulimits))
+	for _, spec := range ulimits {
+		rlimit, err := parseRlimit(spec)
+		if err != nil {
+			die("invalid --ulimit: %s", err)
+		}
+		rlimits = append(rlimits, rlimit)
+	}
+
+	devices := make([]*core.Device, 0, len(deviceSpecs))
+	for _, spec := range deviceSpecs {
+		device, err := parseDevice(spec)
+		if err != nil {
+			die("invalid --device: %s", err)
+		}
+		devices = append(devices, device)
+	}
+
+	environment := map[string]string{}
+	for _, variable := range env {
+		key, value, ok := strings.Cut(variable, "=")
+		if !ok {
+			die("invalid --env: %s", variable)
+		}
+		environment[key] = value
+	}
+
+	request := &core.RunRequest{
+		Application: application,
+		Volume: &core.Volume{
+			Path:         *volume,
+			MountOptions: volumeOptions,
+			ReadOnly:     readOnlyVolume,
+		},
+		Options: &core.RunOptions{
+			Arguments:        args,
+			Environment:      environment,
+			ShareHostNetwork: hostNetwork,
+			Overlay:          overlay,
+			Ports:            ports,
+			Tty:              tty,
+			Processes:        processes,
+			Rlimits:          rlimits,
+			Devices:          devices,
+		},
+	}
+	if unrestrictedPaths || len(maskedPaths) != 0 || len(readonlyPaths) != 0 {
+		request.Options.PathRestrictions = &core.PathRestrictions{
+			MaskedPaths:   maskedPaths,
+			ReadonlyPaths: readonlyPaths,
+		}
+	}
+	if volumeFs != nil {
+		request.Volume.FsType = *volumeFs
+	}
+	if user != nil {
+		parsed, err := parseUser(*user)
+		if err != nil {
+			die("invalid --user: %s", err)
+		}
+		request.Options.User = parsed
+	}
+	if hostname != nil {
+		request.Options.Hostname = *hostname
+	}
+	if workdir != nil {
+		request.Options.WorkingDir = *workdir
+	}
+	if name != nil {
+		request.Name = *name
+	}
+	if stopSignal != nil {
+		request.Options.StopSignal = *stopSignal
+	}
+	if stopTimeout != nil {
+		request.Options.StopTimeout = durationpb.New(*stopTimeout)
+	}
+	if restartPolicy != nil || maxRetries != nil {
+		request.Options.Restart = &core.Restart{}
+	}
+	if restartPolicy != nil {
+		policy, err := parseRestartPolicy(*restartPolicy)
+		if err != nil {
+			die("%s", err)
+		}
+		request.Options.Restart.Policy = policy
+	}
+	if maxRetries != nil {
+		if *maxRetries < 0 {
+			die("invalid --max-retries: %d", *maxRetries)
+		}
+		request.Options.Restart.MaxRetries = uint32(*maxRetries)
+	}
+
+	response, err := f.Client.Run(request)
+	if err != nil {
+		die("could not run application: %s", err)
+	}
+	if interactive {
+		os.Exit(f.attach(response.Name, keys))
+	}
+	fmt.Println(response.Name)
+}
+
+func parseRestartPolicy(policy string) (core.RestartPolicy, error) {
+	switch policy {
+	case "never":
+		return core.RestartPolicy_RESTART_POLICY_NEVER, nil
+	case "on-failure":
+		return core.RestartPolicy_RESTART_POLICY_ON_FAILURE, nil
+	case "always":
+		return core.RestartPolicy_RESTART_POLICY_ALWAYS, nil
+	}
+	return 0, fmt.Errorf("invalid restart policy: %s", policy)
+}
+
+// parseProcesses parses the processes declared in the name=path [args...] format.
+func parseProcesses(specs []string, restart []string) ([]*core.InitProcess, error) {
+	restarted := map[string]bool{}
+	for _, name := range restart {
+		restarted[name] = true
+	}
+
+	processes := make([]*core.InitProcess, 0, len(specs))
+	for _, spec := range specs {
+		name, command, ok := strings.Cut(spec, "=")
+		arguments := strings.Fields(command)
+		if !ok || len(arguments) == 0 {
+			return nil, fmt.Errorf("invalid --process: %s", spec)
+		}
+		processes = append(processes, &core.InitProcess{
+			Name:      name,
+			Arguments: arguments,
+			Restart:   restarted[name],
+		})
+		delete(restarted, name)
+	}
+	for name := range restarted {
+		return nil, fmt.Errorf("invalid --restart-process: %s is not declared", name)
+	}
+	return processes, nil
+}
+
+// parseUser parses the uid[:gid] format, the gid defaults to the uid.
+func parseUser(spec string) (*core.User, error) {
+	uidValue, gidValue, hasGid := strings.Cut(spec, ":")
+	uid, err := strconv.ParseUint(uidValue, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid: %s", uidValue)
+	}
+	gid := uid
+	if hasGid {
+		gid, err = strconv.ParseUint(gidValue, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gid: %s", gidValue)
+		}
+	}
+	return &core.User{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// parseRlimit parses the name=soft[:hard] format, the hard limit defaults to the soft one.
+func parseRlimit(spec string) (*core.Rlimit, error) {
+	name, limits, ok := strings.Cut(spec, "=")
+	if !ok || len(name) == 0 {
+		return nil, fmt.Errorf("missing resource name: %s", spec)
+	}
+	softValue, hardValue, hasHard := strings.Cut(limits, ":")
+	soft, err := strconv.ParseUint(softValue, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid soft limit: %s", softValue)
+	}
+	hard := soft
+	if hasHard {
+		hard, err = strconv.ParseUint(hardValue, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hard limit: %s", hardValue)
+		}
+	}
+	return &core.Rlimit{Type: name, Soft: soft, Hard: hard}, nil
+}
+
+// parseDevice parses the path:c|b:major:minor[:mode[:uid:gid]] format, the mode is octal.
+func parseDevice(spec string) (*core.Device, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 4 || len(parts) == 6 || len(parts) > 7 {
+		return nil, fmt.Errorf("invalid format: %s", spec)
+	}
+
+	device := &core.Device{Path: parts[0]}
+	switch parts[1] {
+	case "c":
+		device.Type = core.DeviceType_DEVICE_TYPE_CHARACTER
+	case "b":
+		device.Type = core.DeviceType_DEVICE_TYPE_BLOCK
+	default:
+		return nil, fmt.Errorf("invalid device type: %s", parts[1])
+	}
+
+	values := make([]uint32, 0, len(parts)-2)
+	for i, part := range parts[2:] {
+		base := 10
+		if i == 2 {
+			base = 8
+		}
+		value, err := strconv.ParseUint(part, base, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", part)
+		}
+		values = append(values, uint32(value))
+	}
+	device.Major, device.Minor = values[0], values[1]
+	if len(values) > 2 {
+		device.Mode = values[2]
+	}
+	if len(values) > 3 {
+		device.Uid, device.Gid = values[3], values[4]
+	}
+	return device, nil
+}
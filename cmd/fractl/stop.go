@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func (f *Frontend) mountStop(root *cobra.Command) {
+	stopCmd := &cobra.Command{
+		Use:   "stop [flags] name",
+		Short: "stop a running process and wait until it exits",
+		Args:  cobra.ExactArgs(1),
+		Run:   f.HandleStop,
+	}
+	stopCmd.Flags().String("signal", "", "signal to send instead of the stop signal of the process")
+	stopCmd.Flags().String("timeout", "", "grace period before the process is killed, for example 30s")
+	root.AddCommand(stopCmd)
+
+	killCmd := &cobra.Command{
+		Use:   "kill [flags] name",
+		Short: "send a signal to a running process",
+		Args:  cobra.ExactArgs(1),
+		Run:   f.HandleKill,
+	}
+	killCmd.Flags().StringP("signal", "s", "SIGKILL", "signal to send")
+	root.AddCommand(killCmd)
+}
+
+func (f *Frontend) HandleStop(cmd *cobra.Command, args []string) {
+	flags := NewFlagErrChain(cmd.Flags())
+	signal := flags.GetString("signal")
+	timeout := flags.GetDuration("timeout")
+	if err := flags.Verify(); err != nil {
+		die("%s", err)
+	}
+
+	request := &core.StopRequest{}
+	if signal != nil {
+		request.Signal = *signal
+	}
+	if timeout != nil {
+		request.Timeout = durationpb.New(*timeout)
+	}
+
+	process, err := f.Client.Stop(args[0], request)
+	if err != nil {
+		die("could not stop process: %s", err)
+	}
+	fmt.Printf("%s exited with code %d\n", process.Name, process.ExitCode)
+}
+
+func (f *Frontend) HandleKill(cmd *cobra.Command, args []string) {
+	signal, err := cmd.Flags().GetString("signal")
+	if err != nil {
+		die("%s", err)
+	}
+
+	if err := f.Client.Signal(args[0], &core.SignalRequest{Signal: signal}); err != nil {
+		die("could not signal process: %s", err)
+	}
+}
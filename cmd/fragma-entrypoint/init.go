@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/process"
+	"google.golang.org/protobuf/proto"
+)
+
+// processRestartDelay prevents a declared process, which keeps failing, from spinning.
+const processRestartDelay = time.Second
+
+// supervisor is the init of the container. It reaps all the processes reparented to it, forwards
+// the signals to the process groups of the application and the declared processes, and restarts
+// the declared processes. It exits together with the application.
+type supervisor struct {
+	// application is the config of the application, the declared processes get the same restrictions.
+	application *entrypoint.Process
+	specs       []process.Spec
+	signals     chan os.Signal
+	restarts    chan process.Spec
+	main        int
+	processes   map[int]process.Spec
+}
+
+// newSupervisor starts receiving the signals, so no SIGCHLD is missed once the processes start.
+func newSupervisor(application *entrypoint.Process, specs []process.Spec) *supervisor {
+	s := &supervisor{
+		application: application,
+		specs:       specs,
+		signals:     make(chan os.Signal, 32),
+		restarts:    make(chan process.Spec, len(specs)),
+		processes:   map[int]process.Spec{},
+	}
+	signal.Notify(s.signals)
+
+	// Outside of a new pid namespace the orphans are reparented to the entrypoint as well.
+	if os.Getpid() != 1 {
+		if err := linux.SetChildSubreaper(); err != nil {
+			die("could not become a subreaper: %s", err)
+		}
+	}
+	return s
+}
+
+func (s *supervisor) stop() {
+	signal.Stop(s.signals)
+}
+
+// start starts the application and the declared processes. Unless the application starts
+// a new session, it is put into its own process group.
+func (s *supervisor) start(cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+	// If the start fails, the entrypoint exits and the error is still reported over the status descriptor.
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// The status descriptor is closed with the files of the command, from now on the exec stage reports the errors.
+	closeExtraFiles(cmd)
+	status = nil
+	s.main = cmd.Process.Pid
+
+	for _, spec := range s.specs {
+		s.startProcess(spec)
+	}
+	return nil
+}
+
+// startProcess starts a declared process with the restrictions of the application, a failure is not fatal.
+func (s *supervisor) startProcess(spec process.Spec) {
+	config := proto.Clone(s.application).(*entrypoint.Process)
+	config.Arguments = spec.Arguments
+	config.WorkingDir = "/"
+
+	cmd, err := execStageCommand(config, nil)
+	if err == nil {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		err = cmd.Start()
+		closeExtraFiles(cmd)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "could not start process %s: %s\n", spec.Name, err)
+		s.scheduleRestart(spec)
+		return
+	}
+	s.processes[cmd.Process.Pid] = spec
+}
+
+func (s *supervisor) scheduleRestart(spec process.Spec) {
+	if !spec.Restart {
+		return
+	}
+	time.AfterFunc(processRestartDelay, func() {
+		s.restarts <- spec
+	})
+}
+
+// forward sends the signal to the process groups, a process, which left its group, receives it directly.
+func (s *supervisor) forward(sig syscall.Signal) {
+	pids := []int{s.main}
+	for pid := range s.processes {
+		pids = append(pids, pid)
+	}
+	for _, pid := range pids {
+		if err := syscall.Kill(-pid, sig); err != nil {
+			_ = syscall.Kill(pid, sig)
+		}
+	}
+}
+
+// reap collects all the exited children, it returns true with the exit status once the application exits.
+func (s *supervisor) reap() (int, bool) {
+	code, exited := 0, false
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if errors.Is(err, syscall.EINTR) {
+			continue
+		}
+		if err != nil || pid <= 0 {
+			return code, exited
+		}
+
+		if pid == s.main {
+			code, exited = exitCode(status), true
+			continue
+		}
+		if spec, ok := s.processes[pid]; ok {
+			delete(s.processes, pid)
+			_, _ = fmt.Fprintf(os.Stderr, "process %s exited with status %d\n", spec.Name, exitCode(status))
+			s.scheduleRestart(spec)
+		}
+	}
+}
+
+// wait supervises the processes until the application exits and returns its exit status.
+func (s *supervisor) wait() int {
+	// The application could have exited before the first SIGCHLD was received.
+	if code, exited := s.reap(); exited {
+		return code
+	}
+
+	for {
+		select {
+		case spec := <-s.restarts:
+			s.startProcess(spec)
+		case sig := <-s.signals:
+			switch sig {
+			case syscall.SIGCHLD:
+				if code, exited := s.reap(); exited {
+					return code
+				}
+			// SIGURG is used by the go runtime for preemption. The kernel sends SIGWINCH to the
+			// foreground process group itself, once the size of its terminal changes.
+			case syscall.SIGURG, syscall.SIGWINCH:
+			default:
+				s.forward(sig.(syscall.Signal))
+			}
+		}
+	}
+}
+
+// exitCode follows the shells, a process killed by a signal results in 128 + the signal number.
+func exitCode(status syscall.WaitStatus) int {
+	if status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return status.ExitStatus()
+}
@@ -1,122 +1,246 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"syscall"
 
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
 	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/process"
+	"github.com/mmbednarek/fragma/pkg/protoutil"
+	"google.golang.org/protobuf/proto"
 )
 
+// status receives the setup errors, it is closed once the application is started.
+var status *os.File
+
+// openStatus keeps the status descriptor from leaking into the children, other than the exec stage of the application.
+func openStatus(fd int) {
+	syscall.CloseOnExec(fd)
+	status = os.NewFile(uintptr(fd), "status")
+}
+
 func die(format string, args ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stderr, format+"\n", args...)
+	message := fmt.Sprintf(format, args...)
+	_, _ = fmt.Fprintln(os.Stderr, message)
+	if status != nil {
+		data, _ := proto.Marshal(&entrypoint.SetupStatus{Error: message})
+		_, _ = status.Write(data)
+	}
 	os.Exit(1)
 }
-func main() {
-	if len(os.Args) < 2 {
-		die("invalid number arguments: %s", os.Args)
-		os.Exit(1)
+
+func propagationFlag(propagation core.MountPropagation) uintptr {
+	switch propagation {
+	case core.MountPropagation_MOUNT_PROPAGATION_PRIVATE:
+		return syscall.MS_PRIVATE
+	case core.MountPropagation_MOUNT_PROPAGATION_SLAVE:
+		return syscall.MS_SLAVE
 	}
+	die("invalid mount propagation: %s", propagation)
+	return 0
+}
 
-	binPath := os.Args[1]
+// waitForDaemon blocks until the daemon writes to the descriptor. If the daemon
+// closes it without writing, the setup has failed and the entrypoint exits.
+func waitForDaemon(fd int) {
+	file := os.NewFile(uintptr(fd), "sync")
+	defer file.Close()
 
-	if err := syscall.Chdir("/"); err != nil {
-		die("could not change dir: %s", err)
+	buff := make([]byte, 1)
+	if _, err := file.Read(buff); err != nil {
+		die("the daemon did not finish the setup: %s", err)
 	}
+}
 
-	if err := linux.SetupCharacterDevices(); err != nil {
-		die("could not setup character devices: %s", err)
+func readConfig(fd int) (*entrypoint.ContainerConfig, error) {
+	var config entrypoint.ContainerConfig
+	if err := protoutil.ReadFd(fd, &config); err != nil {
+		return nil, err
+	}
+	if config.Version != entrypoint.ConfigVersion {
+		return nil, fmt.Errorf("unsupported version %d, expected %d", config.Version, entrypoint.ConfigVersion)
+	}
+	if config.Process == nil || len(config.Process.Arguments) == 0 {
+		return nil, errors.New("no application to run")
 	}
+	return &config, nil
+}
 
-	if err := syscall.Mount("devpts", "/dev/pts", "devpts", 0, ""); err != nil {
-		die("could not mount devpts: %s", err)
+func linuxDevice(device *core.Device) linux.Device {
+	deviceType := uint32(syscall.S_IFCHR)
+	if device.Type == core.DeviceType_DEVICE_TYPE_BLOCK {
+		deviceType = syscall.S_IFBLK
+	}
+	return linux.Device{
+		Path:  device.Path,
+		Type:  deviceType,
+		Major: device.Major,
+		Minor: device.Minor,
+		Mode:  device.Mode,
+		Uid:   device.Uid,
+		Gid:   device.Gid,
 	}
+}
 
-	if err := syscall.Mount("proc", "/proc", "proc", 0, ""); err != nil {
-		die("could not mount proc: %s", err)
+// setupDevices populates the /dev of the rootfs, which is a fresh tmpfs mounted by the daemon.
+func setupDevices(rootfs string, devices []*core.Device) {
+	for _, device := range devices {
+		spec := linuxDevice(device)
+		err := spec.Create(rootfs)
+		// Device nodes cannot be created in a user namespace, the nodes of the host are used instead.
+		if errors.Is(err, syscall.EPERM) {
+			err = spec.BindHost(rootfs)
+		}
+		if err != nil {
+			die("could not create device %s: %s", device.Path, err)
+		}
 	}
 
-	if err := syscall.Mount("sysfs", "/sys", "sysfs", 0, ""); err != nil {
-		die("could not mount sysfs: %s", err)
+	if err := linux.SetupDeviceSymlinks(rootfs); err != nil {
+		die("could not setup device links: %s", err)
 	}
+}
+
+// setupContainer prepares the root, the devices and the limits of the container.
+func setupContainer(config *entrypoint.ContainerConfig) {
+	if len(config.Rootfs) != 0 {
+		if err := linux.SetRootPropagation(propagationFlag(config.Propagation)); err != nil {
+			die("could not set mount propagation: %s", err)
+		}
+
+		// The mounts are made before the root switch, so their sources can come from the host.
+		for _, mount := range config.Mounts {
+			if err := mount.Spec().Apply(config.Rootfs); err != nil {
+				die("could not mount %s: %s", mount.Destination, err)
+			}
+		}
 
-	syscall.Sync()
+		// The devices are created before the root switch as well, the nodes of the host could be needed.
+		setupDevices(config.Rootfs, config.Devices)
 
-	if err := linux.SetupDeviceSymlinks(); err != nil {
-		fmt.Printf("cannot create symlink: %s\n", err)
-		//die("could not setup device links: %s", err)
+		if err := linux.PivotRoot(config.Rootfs); err != nil {
+			die("could not switch root: %s", err)
+		}
 	}
 
-	if err := syscall.Sethostname([]byte("fragma-pod")); err != nil {
-		die("could not set hostname: %s", err)
+	if err := syscall.Chdir("/"); err != nil {
+		die("could not change dir: %s", err)
 	}
 
-	terminal, err := linux.NewTerminal()
-	if err != nil {
-		die("could not create terminal: %s", err)
+	// The paths are restricted once the /dev/null of the container exists.
+	for _, path := range config.MaskedPaths {
+		if err := linux.MaskPath(path); err != nil {
+			die("could not mask %s: %s", path, err)
+		}
+	}
+	for _, path := range config.ReadonlyPaths {
+		if err := linux.ReadonlyPath(path); err != nil {
+			die("could not make %s read-only: %s", path, err)
+		}
 	}
-	defer terminal.Close()
-	fmt.Printf("%s\n", terminal.SlavePath)
 
-	slaveFile, err := terminal.OpenSlave()
-	if err != nil {
-		die("could not open terminal file: %s", err)
+	if len(config.Hostname) != 0 {
+		if err := syscall.Sethostname([]byte(config.Hostname)); err != nil {
+			die("could not set hostname: %s", err)
+		}
 	}
-	defer slaveFile.Close()
 
-	hostTTYAttrib, err := linux.Attr(os.Stdin)
-	if err != nil {
-		die("could not get host terminal attrib: %s", err)
+	// The limits are inherited by all the processes of the container.
+	for _, rlimit := range config.Rlimits {
+		resource, err := linux.RlimitFromName(rlimit.Type)
+		if err != nil {
+			die("invalid resource limit: %s", err)
+		}
+		if err := linux.SetRlimit(resource, rlimit.Soft, rlimit.Hard); err != nil {
+			die("could not set %s: %s", rlimit.Type, err)
+		}
 	}
-	hostTTYAttrib.Raw()
+}
 
-	if err := hostTTYAttrib.Winsz(os.Stdin); err != nil {
-		die("could not get host terminal window size: %s", err)
+func initProcesses(config *entrypoint.ContainerConfig) []process.Spec {
+	specs := make([]process.Spec, 0, len(config.InitProcesses))
+	for _, p := range config.InitProcesses {
+		specs = append(specs, process.Spec{
+			Name:      p.Name,
+			Arguments: p.Arguments,
+			Restart:   p.Restart,
+		})
 	}
+	return specs
+}
 
-	fmt.Printf("terminal info (w = %d, h = %d))\n", hostTTYAttrib.Wz.WsRow, hostTTYAttrib.Wz.WsCol)
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == execStageArg {
+		execStage(os.Args[2:])
+		return
+	}
 
-	if err := hostTTYAttrib.Set(slaveFile); err != nil {
-		die("could not set slave terminal attrib: %s", err)
+	configFd := flag.Int("config-fd", -1, "descriptor to read the container config from")
+	syncFd := flag.Int("sync-fd", -1, "descriptor to wait on until the daemon configures the namespaces")
+	statusFd := flag.Int("status-fd", -1, "descriptor to report the setup errors to, it is closed once the application starts")
+	flag.Parse()
+
+	if *statusFd >= 0 {
+		openStatus(*statusFd)
+	}
+	if *configFd < 0 {
+		die("the -config-fd flag is required")
+	}
+	config, err := readConfig(*configFd)
+	if err != nil {
+		die("invalid container config: %s", err)
 	}
 
-	if err := hostTTYAttrib.Setwinsz(os.Stdin); err != nil {
-		die("could not set slave terminal window size: %s", err)
+	if *syncFd >= 0 {
+		waitForDaemon(*syncFd)
 	}
 
-	go func() {
-		for {
-			n, err := io.Copy(os.Stdout, terminal.MasterFile)
-			if err != nil {
-				fmt.Printf("copy error: %s\n", err)
-			}
-			if n > 0 {
-				fmt.Printf("wrote %d bytes to stdout\n", n)
-			}
-		}
-	}()
-	go func() {
-		for {
-			n, err := io.Copy(terminal.MasterFile, os.Stdin)
-			if err != nil {
-				fmt.Printf("copy error: %s\n", err)
-			}
-			if n > 0 {
-				fmt.Printf("wrote %d bytes to term\n", n)
-			}
+	setupContainer(config)
+
+	cmd, err := execStageCommand(config.Process, status)
+	if err != nil {
+		die("could not prepare the command: %s", err)
+	}
+	processes := initProcesses(config)
+
+	// The daemon keeps the master side of the terminal, so it is passed through to the application,
+	// which starts a new session, so the terminal signals reach its process group only.
+	if config.Tty {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Setsid:  true,
+			Setctty: true,
+			Ctty:    0,
 		}
-	}()
-
-	cmd := exec.Command(binPath)
-	//cmd.Stderr = os.Stderr
-	//cmd.Stdin = os.Stdin
-	//cmd.Stdout = os.Stdout
-	cmd.Stderr = slaveFile
-	cmd.Stdin = slaveFile
-	cmd.Stdout = slaveFile
-	if err := cmd.Run(); err != nil {
+		os.Exit(runApplication(cmd, config.Process, processes))
+	}
+
+	// Detached applications have no terminal, they use the stdio prepared by the daemon.
+	if !linux.Isatty(os.Stdin) {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		os.Exit(runApplication(cmd, config.Process, processes))
+	}
+
+	os.Exit(runWithTerminal(cmd, config.Process, processes))
+}
+
+// runApplication runs the application under the supervisor and returns its exit status, which the entrypoint exits with.
+func runApplication(cmd *exec.Cmd, application *entrypoint.Process, processes []process.Spec) int {
+	supervisor := newSupervisor(application, processes)
+	defer supervisor.stop()
+
+	if err := supervisor.start(cmd); err != nil {
 		die("could not execute the command: %s", err)
 	}
+	return supervisor.wait()
 }
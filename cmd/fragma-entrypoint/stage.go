@@ -0,0 +1,211 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/protoutil"
+	"github.com/mmbednarek/fragma/pkg/seccomp"
+)
+
+const execStageArg = "exec-stage"
+
+// execStageCommand prepares a child of the entrypoint, which applies the restrictions
+// of the process and executes it. If status is set, the exec stage reports its errors
+// there. The files of the command have to be closed with closeExtraFiles once it is started.
+func execStageCommand(process *entrypoint.Process, status *os.File) (*exec.Cmd, error) {
+	config, err := protoutil.Pipe(process)
+	if err != nil {
+		return nil, err
+	}
+
+	extraFiles := []*os.File{config}
+	stageArgs := []string{execStageArg, "-config-fd", strconv.Itoa(2 + len(extraFiles))}
+	if status != nil {
+		extraFiles = append(extraFiles, status)
+		stageArgs = append(stageArgs, "-status-fd", strconv.Itoa(2+len(extraFiles)))
+	}
+
+	// The entrypoint binary is not reachable after the root switch, but /proc/self/exe still resolves to it.
+	// The image does not need to provide a dynamic loader as long as the entrypoint is built with CGO_ENABLED=0.
+	cmd := exec.Command("/proc/self/exe", stageArgs...)
+	cmd.ExtraFiles = extraFiles
+	return cmd, nil
+}
+
+func closeExtraFiles(cmd *exec.Cmd) {
+	for _, file := range cmd.ExtraFiles {
+		file.Close()
+	}
+}
+
+func parseCapabilities(names []string) linux.CapabilitySet {
+	set, err := linux.ParseCapabilitySet(names)
+	if err != nil {
+		die("invalid capabilities: %s", err)
+	}
+	return set
+}
+
+// setUser switches to the user of the process. The permitted capabilities are kept, so the declared
+// ones can still be set afterwards. A user other than root keeps only the ambient ones after execve.
+func setUser(user *core.User) {
+	if err := linux.SetKeepCapabilities(); err != nil {
+		die("could not keep capabilities: %s", err)
+	}
+
+	if len(user.AdditionalGids) > 0 {
+		groups := make([]int, 0, len(user.AdditionalGids))
+		for _, gid := range user.AdditionalGids {
+			groups = append(groups, int(gid))
+		}
+		if err := syscall.Setgroups(groups); err != nil {
+			die("could not set additional groups: %s", err)
+		}
+	}
+	if err := syscall.Setgid(int(user.Gid)); err != nil {
+		die("could not set gid %d: %s", user.Gid, err)
+	}
+	if err := syscall.Setuid(int(user.Uid)); err != nil {
+		die("could not set uid %d: %s", user.Uid, err)
+	}
+}
+
+func setEnvironment(environment []string) {
+	os.Clearenv()
+	for _, variable := range environment {
+		key, value, _ := strings.Cut(variable, "=")
+		if err := os.Setenv(key, value); err != nil {
+			die("invalid environment variable %q: %s", variable, err)
+		}
+	}
+}
+
+func execStage(args []string) {
+	flags := flag.NewFlagSet(execStageArg, flag.ExitOnError)
+	configFd := flags.Int("config-fd", -1, "descriptor to read the process config from")
+	statusFd := flags.Int("status-fd", -1, "descriptor to report the errors to, a successful execve closes it")
+	_ = flags.Parse(args)
+
+	if *statusFd >= 0 {
+		openStatus(*statusFd)
+	}
+
+	var process entrypoint.Process
+	if err := protoutil.ReadFd(*configFd, &process); err != nil {
+		die("could not read process config: %s", err)
+	}
+	if len(process.Arguments) == 0 {
+		die("the process has no arguments")
+	}
+
+	// The binary is looked up in the PATH of the application.
+	setEnvironment(process.Environment)
+	if len(process.WorkingDir) != 0 {
+		if err := os.Chdir(process.WorkingDir); err != nil {
+			die("could not change dir: %s", err)
+		}
+	}
+
+	binPath, err := exec.LookPath(process.Arguments[0])
+	if err != nil {
+		die("could not find the application: %s", err)
+	}
+
+	// Capabilities, no_new_privs and seccomp filters are all per thread,
+	// so they have to be applied by the thread calling execve.
+	runtime.LockOSThread()
+
+	var program seccomp.Program
+	if len(process.SeccompProgram) > 0 {
+		program, err = seccomp.UnmarshalProgram(process.SeccompProgram)
+		if err != nil {
+			die("could not read seccomp program: %s", err)
+		}
+	}
+
+	if process.NoNewPrivileges {
+		if err := linux.SetNoNewPrivs(); err != nil {
+			die("could not set no_new_privs: %s", err)
+		}
+	}
+
+	// Without no_new_privs loading a filter requires CAP_SYS_ADMIN, so it has to happen before the capabilities are dropped.
+	if program != nil && !process.NoNewPrivileges {
+		if err := seccomp.Load(program); err != nil {
+			die("could not load seccomp program: %s", err)
+		}
+	}
+
+	caps := process.Capabilities
+	if caps != nil {
+		if err := linux.DropBoundingSet(parseCapabilities(caps.Bounding)); err != nil {
+			die("could not drop bounding capabilities: %s", err)
+		}
+	}
+
+	if process.User != nil {
+		// Changing the credentials clears the parent death signal, the processes started by Exec rely on it.
+		deathSignal, err := linux.ParentDeathSignal()
+		if err != nil {
+			die("could not read parent death signal: %s", err)
+		}
+		parent := os.Getppid()
+
+		setUser(process.User)
+
+		if deathSignal != 0 {
+			if err := linux.SetParentDeathSignal(deathSignal); err != nil {
+				die("could not set parent death signal: %s", err)
+			}
+			// A parent in the same pid namespace could have exited before the signal was set again.
+			if os.Getppid() != parent {
+				die("the parent exited")
+			}
+		}
+	}
+
+	if caps != nil {
+		bounding := parseCapabilities(caps.Bounding)
+		effective := parseCapabilities(caps.Effective)
+		permitted := parseCapabilities(caps.Permitted)
+		ambient := parseCapabilities(caps.Ambient)
+
+		// Otherwise execve would grant root the whole bounding set again. Once root is treated as any other user,
+		// its effective set has to be ambient to survive execve.
+		root := process.User == nil || process.User.Uid == 0
+		if root && (effective != bounding || permitted != bounding) {
+			if err := linux.RestrictRoot(); err != nil {
+				die("could not restrict root: %s", err)
+			}
+			ambient |= effective
+		}
+
+		// Ambient capabilities must also be inheritable.
+		if err := linux.SetCapabilities(effective, permitted, ambient); err != nil {
+			die("could not set capabilities: %s", err)
+		}
+
+		if err := linux.SetAmbientCapabilities(ambient); err != nil {
+			die("could not set ambient capabilities: %s", err)
+		}
+	}
+
+	if program != nil && process.NoNewPrivileges {
+		if err := seccomp.Load(program); err != nil {
+			die("could not load seccomp program: %s", err)
+		}
+	}
+
+	if err := syscall.Exec(binPath, process.Arguments, os.Environ()); err != nil {
+		die("could not execute the command: %s", err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+// outputDrainTimeout limits waiting for the rest of the output, the processes
+// left in the background could keep the terminal open after the application exits.
+const outputDrainTimeout = time.Second
+
+// resizeTerminal copies the window size of the host terminal to the terminal of the application.
+func resizeTerminal(terminal linux.Terminal) error {
+	var attr linux.Termios
+	if err := attr.Winsz(os.Stdin); err != nil {
+		return err
+	}
+	return attr.Setwinsz(terminal.MasterFile)
+}
+
+// forwardResize propagates the size of the host terminal on every SIGWINCH.
+func forwardResize(terminal linux.Terminal) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+
+	go func() {
+		for range signals {
+			_ = resizeTerminal(terminal)
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// runWithTerminal runs the application on a new terminal, which is proxied to the host terminal.
+// The host terminal is switched to the raw mode, so the terminal of the application handles
+// the special characters, and it is restored before the entrypoint exits.
+func runWithTerminal(cmd *exec.Cmd, application *entrypoint.Process, processes []process.Spec) int {
+	hostAttr, err := linux.Attr(os.Stdin)
+	if err != nil {
+		die("could not get host terminal attributes: %s", err)
+	}
+
+	terminal, err := linux.NewTerminal()
+	if err != nil {
+		die("could not create terminal: %s", err)
+	}
+	defer terminal.Close()
+
+	slaveFile, err := terminal.OpenSlave()
+	if err != nil {
+		die("could not open terminal file: %s", err)
+	}
+
+	// The terminal of the application starts with the settings of the host terminal.
+	if err := hostAttr.Set(slaveFile); err != nil {
+		die("could not set terminal attributes: %s", err)
+	}
+	if err := resizeTerminal(terminal); err != nil {
+		die("could not set terminal window size: %s", err)
+	}
+	stopResize := forwardResize(terminal)
+	defer stopResize()
+
+	raw := hostAttr
+	raw.Raw()
+	if err := raw.Set(os.Stdin); err != nil {
+		die("could not set host terminal attributes: %s", err)
+	}
+	defer func() {
+		_ = hostAttr.Set(os.Stdin)
+	}()
+
+	cmd.Stdin = slaveFile
+	cmd.Stdout = slaveFile
+	cmd.Stderr = slaveFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid:  true,
+		Setctty: true,
+		Ctty:    0,
+	}
+
+	copied := terminal.Pump(os.Stdin, os.Stdout)
+
+	supervisor := newSupervisor(application, processes)
+	defer supervisor.stop()
+	if err := supervisor.start(cmd); err != nil {
+		_ = hostAttr.Set(os.Stdin)
+		die("could not execute the command: %s", err)
+	}
+	slaveFile.Close()
+	code := supervisor.wait()
+
+	select {
+	case <-copied:
+	case <-time.After(outputDrainTimeout):
+	}
+	return code
+}
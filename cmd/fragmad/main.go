@@ -3,43 +3,120 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/fasthttp/router"
+	core_v1_det "github.com/mmbednarek/fragma/api/fragma/core/v1/detail"
+	"github.com/mmbednarek/fragma/daemon/rest/v1"
+	"github.com/mmbednarek/fragma/daemon/runtime/v1"
 	"github.com/mmbednarek/fragma/daemon/service/v1"
+	"github.com/mmbednarek/fragma/model"
 	"github.com/mmbednarek/fragma/pkg/log"
 	_ "github.com/mmbednarek/fragma/pkg/log/formatter"
+	"github.com/mmbednarek/fragma/pkg/network"
+	"github.com/mmbednarek/fragma/pkg/storage"
+	"github.com/valyala/fasthttp"
 )
 
+type Crud = *model.CrudService[storage.Storage]
+
+func getEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); len(value) != 0 {
+		return value
+	}
+	return defaultValue
+}
+
 func main() {
 	ctx := context.Background()
-	srv := service.NewService()
 
-	img := os.Getenv("FRAGMA_IMAGE")
-	if len(img) == 0 {
-		img = "./img"
+	var opts []func(s *service.Service)
+	if entrypoint := os.Getenv("FRAGMA_ENTRYPOINT"); len(entrypoint) != 0 {
+		opts = append(opts, service.WithEntrypoint(entrypoint))
 	}
 
-	bin := "/usr/bin/bash"
-	if len(os.Args) > 1 {
-		bin = os.Args[1]
+	store, err := storage.NewStorage(getEnv("FRAGMA_STORE", "/var/lib/fragma/store"))
+	if err != nil {
+		log.With(ctx, "msg", err).Error("could not open storage")
+		return
 	}
+	crud := model.NewCrudService[storage.Storage](store)
 
-	app := &core.Application{
-		Name: "Bash SHELL",
-		Path: bin,
+	allocator, err := network.NewAllocator(store, getEnv("FRAGMA_SUBNET", network.DefaultSubnet))
+	if err != nil {
+		log.With(ctx, "msg", err).Error("could not create address allocator")
+		return
+	}
+	opts = append(opts, service.WithNetwork(network.NewManager(network.DefaultBridge, allocator)))
+	opts = append(opts, service.WithProcessRecorder(crudRecorder{crud: &crud}))
+	opts = append(opts, service.WithLogDir(getEnv("FRAGMA_LOG_DIR", "/var/lib/fragma/logs")))
+	if value := os.Getenv("FRAGMA_IMAGE_IDLE_TIMEOUT"); len(value) != 0 {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.With(ctx, "msg", err).Error("invalid image idle timeout")
+			return
+		}
+		opts = append(opts, service.WithImageIdleTimeout(timeout))
+	}
+	if value := os.Getenv("FRAGMA_SETUP_TIMEOUT"); len(value) != 0 {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.With(ctx, "msg", err).Error("invalid setup timeout")
+			return
+		}
+		opts = append(opts, service.WithSetupTimeout(timeout))
 	}
 
-	volume := &core.Volume{
-		Path: img,
+	journal, err := service.OpenJournal(getEnv("FRAGMA_JOURNAL", "/var/lib/fragma/journal.json"))
+	if err != nil {
+		log.With(ctx, "msg", err).Error("could not open journal")
+		return
 	}
+	opts = append(opts, service.WithJournal(journal))
 
-	options := &core.RunOptions{
-		Arguments:        os.Args[1:],
-		ShareHostNetwork: false,
+	srv := service.NewService(opts...)
+	crud.AddController(volumeController{store: store, service: srv})
+
+	// The resources of the previous instance are released if it crashed, before any run is started.
+	resources, err := srv.CollectGarbage(ctx, false)
+	if err != nil {
+		log.With(ctx, "msg", err).Warn("could not release the leaked resources")
+	}
+	for _, resource := range resources {
+		if len(resource.Error) != 0 {
+			log.With(ctx, "path", resource.Path, "run", resource.Run, "msg", resource.Error).Warn("could not release leaked resource")
+			continue
+		}
+		log.With(ctx, "path", resource.Path, "run", resource.Run).Info("released leaked resource")
 	}
 
-	log.With(ctx).Info("starting application")
-	if err := srv.RunApplication(ctx, volume, app, options); err != nil {
-		log.With(ctx, "msg", err).Error("could not run application")
+	rt := router.New()
+	restApi := rest.NewRest[Crud](&crud,
+		rest.WithApi[Crud](core_v1_det.ApiDetail{}),
+	)
+	restApi.Route(rt)
+	runtime.NewRuntime(srv).Route(rt)
+
+	listen := getEnv("FRAGMA_LISTEN", "127.0.0.1:8000")
+	server := &fasthttp.Server{Handler: rt.Handler}
+	go func() {
+		log.With(ctx, "address", listen).Info("serving api")
+		if err := server.ListenAndServe(listen); err != nil {
+			log.With(ctx, "msg", err).Error("could not serve api")
+			os.Exit(1)
+		}
+	}()
+
+	// The runs are stopped before exiting, otherwise their mounts and loop devices would be left behind.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-signals
+	log.With(ctx, "signal", sig.String()).Info("stopping processes")
+
+	srv.StopAll(ctx)
+	if err := server.Shutdown(); err != nil {
+		log.With(ctx, "msg", err).Warn("could not shutdown api server")
 	}
 }
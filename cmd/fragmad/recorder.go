@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/model"
+	"github.com/mmbednarek/fragma/pkg/storage"
+)
+
+const processKind = "fragma.core.v1.Process"
+
+// crudRecorder stores the processes as objects, so they are served by the rest api.
+type crudRecorder struct {
+	crud *model.CrudService[storage.Storage]
+}
+
+func (r crudRecorder) RecordProcess(process *core.Process) error {
+	err := r.crud.Update(&model.Object{
+		Kind: processKind,
+		Metadata: model.Metadata{
+			Name: process.Name,
+		},
+		Spec: model.Spec{Message: process},
+	})
+	if err != nil {
+		return fmt.Errorf("r.crud.Update: %w", err)
+	}
+	return nil
+}
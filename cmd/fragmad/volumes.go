@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/daemon/service/v1"
+	"github.com/mmbednarek/fragma/model"
+	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/storage"
+)
+
+const volumeKind = "fragma.core.v1.Volume"
+
+// volumeController fills in the live status of the volumes and refuses to delete the volumes in use.
+type volumeController struct {
+	store   storage.Storage
+	service *service.Service
+}
+
+func (c volumeController) ValidateUpdate(obj *model.Object) error {
+	volume, ok := obj.Spec.Message.(*core.Volume)
+	if !ok {
+		return nil
+	}
+	if err := service.ValidateVolume(volume); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrInvalidObject, err)
+	}
+	// The status is never stored, it is read from the system.
+	volume.Status = nil
+	volume.Name = ""
+	return nil
+}
+
+func (c volumeController) ValidateDelete(typeName string, name string) error {
+	if typeName != volumeKind {
+		return nil
+	}
+	obj, err := c.store.ReadObject(typeName, name)
+	if err != nil {
+		// There is nothing to check for an object, which does not exist.
+		if errors.Is(err, model.ErrObjectNotFound) {
+			return nil
+		}
+		return fmt.Errorf("c.store.ReadObject: %w", err)
+	}
+	volume, ok := obj.Spec.Message.(*core.Volume)
+	if !ok {
+		return nil
+	}
+	// The object is removed along with the check, so the removal by the crud service afterwards finds nothing.
+	err = c.service.DeleteVolume(volume, func() error {
+		return c.store.RemoveObject(typeName, name)
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrVolumeInUse) {
+			return fmt.Errorf("%w: %w", model.ErrObjectInUse, err)
+		}
+		return fmt.Errorf("c.service.DeleteVolume: %w", err)
+	}
+	return nil
+}
+
+func (c volumeController) OnDelete(typeName string, name string) {}
+
+func (c volumeController) OnUpdate(obj *model.Object) {}
+
+func (c volumeController) OnRead(obj *model.Object) {
+	volume, ok := obj.Spec.Message.(*core.Volume)
+	if !ok {
+		return
+	}
+	volume.Name = obj.Metadata.Name
+
+	status, err := c.service.VolumeStatus(volume)
+	if err != nil {
+		log.With(context.Background(), "volume", obj.Metadata.Name, "msg", err).Warn("could not read volume status")
+		return
+	}
+	volume.Status = status
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/fasthttp/router"
@@ -44,6 +45,17 @@ func NewRest[TCrud CrudService](crud TCrud, opts ...func(rest *Rest[TCrud])) Res
 	return rest
 }
 
+// refusalStatus returns the status of a change refused by a validator, the reason is passed on to the client.
+func refusalStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, model.ErrInvalidObject):
+		return fasthttp.StatusBadRequest, true
+	case errors.Is(err, model.ErrObjectInUse):
+		return fasthttp.StatusConflict, true
+	}
+	return 0, false
+}
+
 func (r *Rest[TCrud]) GetResource(ctx *fasthttp.RequestCtx, objectDetail model.ObjectDetail) {
 	name := ctx.UserValue("name").(string)
 	obj, err := r.crud.Read(objectDetail.FullName, name)
@@ -75,6 +87,10 @@ func (r *Rest[TCrud]) WriteResource(ctx *fasthttp.RequestCtx, objectDetail model
 	}
 
 	if err := r.crud.Update(&obj); err != nil {
+		if status, ok := refusalStatus(err); ok {
+			ctx.Error(err.Error(), status)
+			return
+		}
 		ctx.Error("could not read object", fasthttp.StatusNotFound)
 		return
 	}
@@ -86,6 +102,10 @@ func (r *Rest[TCrud]) DeleteResource(ctx *fasthttp.RequestCtx, objectDetail mode
 	name := ctx.UserValue("name").(string)
 
 	if err := r.crud.Delete(objectDetail.FullName, name); err != nil {
+		if status, ok := refusalStatus(err); ok {
+			ctx.Error(err.Error(), status)
+			return
+		}
 		ctx.Error("could not delete object", fasthttp.StatusNotFound)
 		return
 	}
@@ -116,10 +136,16 @@ func (r *Rest[TCrud]) GetAllResources(ctx *fasthttp.RequestCtx, objectDetail mod
 
 func (r *Rest[TCrud]) RequestHandler() fasthttp.RequestHandler {
 	rt := router.New()
+	r.Route(rt)
+	return rt.Handler
+}
 
+// Route registers the object routes, so they can share the router with other apis.
+func (r *Rest[TCrud]) Route(rt *router.Router) {
 	for _, api := range r.apis {
 		objects := api.Objects()
 		for _, object := range objects {
+			object := object
 			rt.GET(fmt.Sprintf("/apis/%s/%s/{name}", api.Name(), object.PluralName), func(ctx *fasthttp.RequestCtx) {
 				r.GetResource(ctx, object)
 			})
@@ -141,6 +167,4 @@ func (r *Rest[TCrud]) RequestHandler() fasthttp.RequestHandler {
 			})
 		}
 	}
-
-	return rt.Handler
 }
@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/stream"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Attach upgrades the connection, which carries the input and the output of the terminal of the process.
+// Closing the connection detaches from the terminal, the process keeps running.
+func (r *Runtime) Attach(ctx *fasthttp.RequestCtx) {
+	name := ctx.UserValue("name").(string)
+	if !strings.EqualFold(string(ctx.Request.Header.Peek("Upgrade")), stream.Protocol) {
+		ctx.Error(fmt.Sprintf("attach requires an upgrade to %s", stream.Protocol), fasthttp.StatusUpgradeRequired)
+		return
+	}
+
+	request := &core.AttachRequest{}
+	if err := protojson.Unmarshal(ctx.PostBody(), request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+	if err := r.service.CheckAttach(name); err != nil {
+		ctx.Error(fmt.Sprintf("could not attach: %s", err), errorStatus(err))
+		return
+	}
+
+	ctx.Response.Header.Set("Connection", "Upgrade")
+	ctx.Response.Header.Set("Upgrade", stream.Protocol)
+	ctx.SetStatusCode(fasthttp.StatusSwitchingProtocols)
+	ctx.Hijack(func(conn net.Conn) {
+		r.serveAttach(name, request, stream.NewConn(conn, conn))
+	})
+}
+
+func (r *Runtime) serveAttach(name string, request *core.AttachRequest, conn *stream.Conn) {
+	attachment, err := r.service.Attach(name, conn.Writer(stream.FrameStdout), uint16(request.Rows), uint16(request.Cols))
+	if err != nil {
+		_ = conn.WriteFrame(stream.FrameError, []byte(err.Error()))
+		return
+	}
+
+	go func() {
+		for {
+			frame, err := conn.ReadFrame()
+			if err != nil {
+				attachment.Detach()
+				return
+			}
+
+			switch frame.Type {
+			case stream.FrameStdin:
+				_, _ = attachment.Write(frame.Payload)
+			case stream.FrameResize:
+				rows, cols, err := stream.DecodeResize(frame.Payload)
+				if err == nil {
+					_ = attachment.Resize(rows, cols)
+				}
+			}
+		}
+	}()
+
+	<-attachment.Done()
+	if code, exited := attachment.ExitCode(); exited {
+		_ = conn.WriteFrame(stream.FrameExit, stream.EncodeExit(code))
+	}
+}
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/daemon/service/v1"
+	"github.com/mmbednarek/fragma/pkg/stream"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Exec upgrades the connection, which carries the stdio of the started process as stream frames.
+func (r *Runtime) Exec(ctx *fasthttp.RequestCtx) {
+	name := ctx.UserValue("name").(string)
+	if !strings.EqualFold(string(ctx.Request.Header.Peek("Upgrade")), stream.Protocol) {
+		ctx.Error(fmt.Sprintf("exec requires an upgrade to %s", stream.Protocol), fasthttp.StatusUpgradeRequired)
+		return
+	}
+
+	request := &core.ExecRequest{}
+	if err := protojson.Unmarshal(ctx.PostBody(), request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+	if _, err := r.service.FindRun(name); err != nil {
+		ctx.Error(fmt.Sprintf("could not exec: %s", err), errorStatus(err))
+		return
+	}
+
+	ctx.Response.Header.Set("Connection", "Upgrade")
+	ctx.Response.Header.Set("Upgrade", stream.Protocol)
+	ctx.SetStatusCode(fasthttp.StatusSwitchingProtocols)
+	ctx.Hijack(func(conn net.Conn) {
+		r.serveExec(name, request, stream.NewConn(conn, conn))
+	})
+}
+
+func (r *Runtime) serveExec(name string, request *core.ExecRequest, conn *stream.Conn) {
+	stdio := service.Stdio{
+		Stdout: conn.Writer(stream.FrameStdout),
+		Stderr: conn.Writer(stream.FrameStderr),
+	}
+
+	// The process reads from a pipe directly, so waiting for it does not depend on the client closing stdin.
+	var stdinWriter *os.File
+	if request.Stdin {
+		stdinReader, writer, err := os.Pipe()
+		if err != nil {
+			_ = conn.WriteFrame(stream.FrameError, []byte(err.Error()))
+			return
+		}
+		defer stdinReader.Close()
+		defer writer.Close()
+		stdio.Stdin = stdinReader
+		stdinWriter = writer
+	}
+
+	process, err := r.service.Exec(context.Background(), name, request, stdio)
+	if err != nil {
+		_ = conn.WriteFrame(stream.FrameError, []byte(err.Error()))
+		return
+	}
+
+	go func() {
+		for {
+			frame, err := conn.ReadFrame()
+			if err != nil {
+				// Nobody waits for the output anymore.
+				_ = process.Kill()
+				return
+			}
+
+			switch frame.Type {
+			case stream.FrameStdin:
+				if stdinWriter != nil {
+					_, _ = stdinWriter.Write(frame.Payload)
+				}
+			case stream.FrameCloseStdin:
+				if stdinWriter != nil {
+					_ = stdinWriter.Close()
+				}
+			case stream.FrameResize:
+				rows, cols, err := stream.DecodeResize(frame.Payload)
+				if err == nil {
+					_ = process.Resize(rows, cols)
+				}
+			}
+		}
+	}()
+
+	code, err := process.Wait()
+	if err != nil {
+		_ = conn.WriteFrame(stream.FrameError, []byte(err.Error()))
+		return
+	}
+	_ = conn.WriteFrame(stream.FrameExit, stream.EncodeExit(code))
+}
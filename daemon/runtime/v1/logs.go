@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/logfile"
+	"github.com/valyala/fasthttp"
+)
+
+func logOptions(args *fasthttp.Args) (logfile.ReadOptions, error) {
+	options := logfile.ReadOptions{
+		Tail:   -1,
+		Follow: args.GetBool("follow"),
+	}
+
+	if since := args.Peek("since"); len(since) != 0 {
+		parsed, err := time.Parse(time.RFC3339Nano, string(since))
+		if err != nil {
+			return options, fmt.Errorf("invalid since: %w", err)
+		}
+		options.Since = parsed
+	}
+
+	if tail := args.Peek("tail"); len(tail) != 0 {
+		parsed, err := strconv.Atoi(string(tail))
+		if err != nil {
+			return options, fmt.Errorf("invalid tail: %w", err)
+		}
+		options.Tail = parsed
+	}
+
+	return options, nil
+}
+
+// Logs streams the records of the process as json lines, with follow the response lasts until the process exits.
+func (r *Runtime) Logs(ctx *fasthttp.RequestCtx) {
+	name := ctx.UserValue("name").(string)
+
+	options, err := logOptions(ctx.QueryArgs())
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if err := r.service.CheckLogs(name); err != nil {
+		ctx.Error(fmt.Sprintf("could not read logs: %s", err), errorStatus(err))
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetContentType("application/x-ndjson")
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		err := r.service.Logs(context.Background(), name, options, func(record logfile.Record) error {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+			// A failed flush means the client is gone.
+			return w.Flush()
+		})
+		if err != nil {
+			log.With(context.Background(), "process", name, "msg", err).Warn("could not stream logs")
+		}
+	})
+}
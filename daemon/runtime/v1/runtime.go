@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fasthttp/router"
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/daemon/service/v1"
+	"github.com/mmbednarek/fragma/pkg/network"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const Prefix = "/runtime/v1"
+
+// Runtime exposes the operations on the processes of the node, the objects are served by the rest api.
+type Runtime struct {
+	service *service.Service
+}
+
+func NewRuntime(srv *service.Service) *Runtime {
+	return &Runtime{service: srv}
+}
+
+func (r *Runtime) Route(rt *router.Router) {
+	rt.POST(Prefix+"/run", r.Run)
+	rt.POST(Prefix+"/processes/{name}/exec", r.Exec)
+	rt.POST(Prefix+"/processes/{name}/attach", r.Attach)
+	rt.POST(Prefix+"/processes/{name}/stop", r.Stop)
+	rt.POST(Prefix+"/processes/{name}/signal", r.Signal)
+	rt.GET(Prefix+"/processes/{name}/logs", r.Logs)
+	rt.POST(Prefix+"/admin/gc", r.CollectGarbage)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRunNotFound):
+		return fasthttp.StatusNotFound
+	case errors.Is(err, service.ErrRunExists),
+		errors.Is(err, service.ErrVolumeInUse):
+		return fasthttp.StatusConflict
+	case errors.Is(err, service.ErrServiceStopped):
+		return fasthttp.StatusServiceUnavailable
+	case errors.Is(err, service.ErrInvalidName),
+		errors.Is(err, service.ErrInvalidMount),
+		errors.Is(err, service.ErrInvalidMapping),
+		errors.Is(err, service.ErrInvalidCapabilities),
+		errors.Is(err, service.ErrInvalidExec),
+		errors.Is(err, service.ErrInvalidSignal),
+		errors.Is(err, service.ErrInvalidRestart),
+		errors.Is(err, service.ErrNoTerminal),
+		errors.Is(err, service.ErrInvalidProcess),
+		errors.Is(err, service.ErrInvalidConfig),
+		errors.Is(err, service.ErrInvalidDevice),
+		errors.Is(err, service.ErrInvalidVolume),
+		errors.Is(err, network.ErrInvalidPortMapping):
+		return fasthttp.StatusBadRequest
+	case errors.Is(err, service.ErrContainerSetup),
+		errors.Is(err, service.ErrSetupTimeout):
+		return fasthttp.StatusUnprocessableEntity
+	}
+	return fasthttp.StatusInternalServerError
+}
+
+func writeMessage(ctx *fasthttp.RequestCtx, status int, msg proto.Message) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		ctx.Error("could not marshal message", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("application/json")
+	if _, err := ctx.Write(data); err != nil {
+		ctx.Error("could not write message", fasthttp.StatusInternalServerError)
+	}
+}
+
+func (r *Runtime) Run(ctx *fasthttp.RequestCtx) {
+	var request core.RunRequest
+	if err := protojson.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+	if request.Application == nil || request.Volume == nil {
+		ctx.Error("application and volume are required", fasthttp.StatusBadRequest)
+		return
+	}
+	if request.Options == nil {
+		request.Options = &core.RunOptions{}
+	}
+
+	// The run outlives the request.
+	run, err := r.service.StartApplication(context.Background(), request.Name, request.Volume, request.Application, request.Options, service.Stdio{})
+	if err != nil {
+		ctx.Error(fmt.Sprintf("could not run application: %s", err), errorStatus(err))
+		return
+	}
+
+	writeMessage(ctx, fasthttp.StatusCreated, &core.RunResponse{Name: run.Name})
+}
+
+// Stop blocks until the process exits and responds with its final state.
+func (r *Runtime) Stop(ctx *fasthttp.RequestCtx) {
+	name := ctx.UserValue("name").(string)
+
+	var request core.StopRequest
+	if err := protojson.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+
+	process, err := r.service.Stop(context.Background(), name, request.Signal, request.Timeout)
+	if err != nil {
+		ctx.Error(fmt.Sprintf("could not stop process: %s", err), errorStatus(err))
+		return
+	}
+
+	writeMessage(ctx, fasthttp.StatusOK, process)
+}
+
+func (r *Runtime) Signal(ctx *fasthttp.RequestCtx) {
+	name := ctx.UserValue("name").(string)
+
+	var request core.SignalRequest
+	if err := protojson.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+
+	if err := r.service.SignalRun(name, request.Signal); err != nil {
+		ctx.Error(fmt.Sprintf("could not signal process: %s", err), errorStatus(err))
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// CollectGarbage releases the loop devices, the mounts and the network resources left behind by the runs, which no longer exist.
+func (r *Runtime) CollectGarbage(ctx *fasthttp.RequestCtx) {
+	var request core.GcRequest
+	if err := protojson.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.Error("could not unmarshal request", fasthttp.StatusBadRequest)
+		return
+	}
+
+	resources, err := r.service.CollectGarbage(context.Background(), request.DryRun)
+	if err != nil {
+		ctx.Error(fmt.Sprintf("could not collect garbage: %s", err), errorStatus(err))
+		return
+	}
+
+	writeMessage(ctx, fasthttp.StatusOK, &core.GcResponse{Resources: resources})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+// defaultCapabilities is the minimal set granted to applications, which do not declare their capabilities.
+var defaultCapabilities = linux.NewCapabilitySet(
+	linux.CAP_AUDIT_WRITE,
+	linux.CAP_CHOWN,
+	linux.CAP_DAC_OVERRIDE,
+	linux.CAP_FOWNER,
+	linux.CAP_FSETID,
+	linux.CAP_KILL,
+	linux.CAP_MKNOD,
+	linux.CAP_NET_BIND_SERVICE,
+	linux.CAP_NET_RAW,
+	linux.CAP_SETFCAP,
+	linux.CAP_SETGID,
+	linux.CAP_SETPCAP,
+	linux.CAP_SETUID,
+	linux.CAP_SYS_CHROOT,
+)
+
+var (
+	ErrInvalidCapabilities = errors.New("invalid capabilities")
+)
+
+type capabilitySets struct {
+	Bounding  linux.CapabilitySet
+	Effective linux.CapabilitySet
+	Permitted linux.CapabilitySet
+	Ambient   linux.CapabilitySet
+}
+
+func newCapabilitySets(caps *core.Capabilities) (capabilitySets, error) {
+	if caps == nil {
+		return capabilitySets{
+			Bounding:  defaultCapabilities,
+			Effective: defaultCapabilities,
+			Permitted: defaultCapabilities,
+		}, nil
+	}
+
+	var result capabilitySets
+	var err error
+	if result.Bounding, err = linux.ParseCapabilitySet(caps.Bounding); err != nil {
+		return capabilitySets{}, fmt.Errorf("%w: %s", ErrInvalidCapabilities, err)
+	}
+	if result.Effective, err = linux.ParseCapabilitySet(caps.Effective); err != nil {
+		return capabilitySets{}, fmt.Errorf("%w: %s", ErrInvalidCapabilities, err)
+	}
+	if result.Permitted, err = linux.ParseCapabilitySet(caps.Permitted); err != nil {
+		return capabilitySets{}, fmt.Errorf("%w: %s", ErrInvalidCapabilities, err)
+	}
+	if result.Ambient, err = linux.ParseCapabilitySet(caps.Ambient); err != nil {
+		return capabilitySets{}, fmt.Errorf("%w: %s", ErrInvalidCapabilities, err)
+	}
+
+	if result.Effective&^result.Permitted != 0 {
+		return capabilitySets{}, fmt.Errorf("%w: effective set is not a subset of the permitted set", ErrInvalidCapabilities)
+	}
+	if result.Ambient&^result.Permitted != 0 {
+		return capabilitySets{}, fmt.Errorf("%w: ambient set is not a subset of the permitted set", ErrInvalidCapabilities)
+	}
+
+	return result, nil
+}
+
+// config returns the sets passed to the entrypoint, which applies them right before executing the application.
+func (c capabilitySets) config() *core.Capabilities {
+	return &core.Capabilities{
+		Bounding:  c.Bounding.Names(),
+		Effective: c.Effective.Names(),
+		Permitted: c.Permitted.Names(),
+		Ambient:   c.Ambient.Names(),
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrInvalidConfig  = errors.New("invalid container config")
+	ErrContainerSetup = errors.New("could not set up the container")
+	ErrSetupTimeout   = errors.New("the container setup timed out")
+)
+
+// DefaultSetupTimeout limits waiting for the entrypoint to set up the container and start the application.
+const DefaultSetupTimeout = time.Minute
+
+// The descriptors of the entrypoint, in the order of cmd.ExtraFiles.
+const (
+	// configFd is the descriptor the entrypoint reads the ContainerConfig from.
+	configFd = 3
+	// syncFd is the descriptor the entrypoint waits on, until the daemon finishes configuring its namespaces.
+	syncFd = 4
+	// statusFd is the descriptor the entrypoint reports the setup errors to, it is closed once the application starts.
+	statusFd = 5
+)
+
+const (
+	defaultHostname   = "fragma-pod"
+	defaultWorkingDir = "/root"
+)
+
+// The hostname is limited to HOST_NAME_MAX characters.
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]{0,62}[a-zA-Z0-9])?$`)
+
+// systemMounts precede the mounts of the application, so they can be covered by them.
+// The sysfs is not namespaced, so it is read-only, as it would otherwise configure the devices and the kernel of the host.
+var systemMounts = append([]linux.MountSpec{
+	{Kind: linux.MountProc, Destination: "/proc"},
+	{Kind: linux.MountSysfs, Destination: "/sys", ReadOnly: true},
+}, devMounts...)
+
+func hostname(options *core.RunOptions) string {
+	if len(options.Hostname) == 0 {
+		return defaultHostname
+	}
+	return options.Hostname
+}
+
+func workingDir(options *core.RunOptions) string {
+	if len(options.WorkingDir) == 0 {
+		return defaultWorkingDir
+	}
+	return options.WorkingDir
+}
+
+// validateConfig checks the options applied by the entrypoint, before anything is set up.
+func validateConfig(options *core.RunOptions) error {
+	if len(options.Hostname) != 0 && !hostnamePattern.MatchString(options.Hostname) {
+		return fmt.Errorf("%w: invalid hostname %q", ErrInvalidConfig, options.Hostname)
+	}
+	if len(options.WorkingDir) != 0 && !filepath.IsAbs(options.WorkingDir) {
+		return fmt.Errorf("%w: working dir %s is not absolute", ErrInvalidConfig, options.WorkingDir)
+	}
+
+	if err := validateDevices(options.Devices); err != nil {
+		return err
+	}
+	if err := validatePathRestrictions(options.PathRestrictions); err != nil {
+		return err
+	}
+
+	resources := map[int]bool{}
+	for _, rlimit := range options.Rlimits {
+		resource, err := linux.RlimitFromName(rlimit.Type)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+		}
+		if resources[resource] {
+			return fmt.Errorf("%w: duplicated resource limit %s", ErrInvalidConfig, rlimit.Type)
+		}
+		resources[resource] = true
+		if rlimit.Soft > rlimit.Hard {
+			return fmt.Errorf("%w: soft limit of %s exceeds the hard limit", ErrInvalidConfig, rlimit.Type)
+		}
+	}
+	return nil
+}
+
+// containerConfig describes the container, which the entrypoint sets up before it executes the application.
+func containerConfig(rootfs string, application *core.Application, options *core.RunOptions, caps capabilitySets, mounts []linux.MountSpec, seccompProgram []byte) *entrypoint.ContainerConfig {
+	arguments := []string{application.Path}
+	if len(options.Arguments) > 0 {
+		arguments = append(arguments, options.Arguments[1:]...)
+	}
+
+	config := &entrypoint.ContainerConfig{
+		Version:     entrypoint.ConfigVersion,
+		Rootfs:      rootfs,
+		Propagation: options.MountPropagation,
+		Hostname:    hostname(options),
+		Devices:     containerDevices(options.Devices),
+		Rlimits:     options.Rlimits,
+		Tty:         options.Tty,
+		Process: &entrypoint.Process{
+			Arguments:       arguments,
+			Environment:     applicationEnv(options.Environment),
+			WorkingDir:      workingDir(options),
+			User:            options.User,
+			NoNewPrivileges: !options.AllowNewPrivileges,
+			Capabilities:    caps.config(),
+			SeccompProgram:  seccompProgram,
+		},
+		InitProcesses: options.Processes,
+	}
+	config.MaskedPaths, config.ReadonlyPaths = pathRestrictions(options.PathRestrictions)
+	for _, spec := range systemMounts {
+		config.Mounts = append(config.Mounts, entrypoint.NewMount(spec))
+	}
+	for _, spec := range mounts {
+		config.Mounts = append(config.Mounts, entrypoint.NewMount(spec))
+	}
+	return config
+}
+
+// readSetupStatus blocks until the entrypoint starts the application or fails to set up the container,
+// for at most the timeout or until the context is done. It returns the error reported by the entrypoint,
+// an empty status means the application was started.
+func readSetupStatus(ctx context.Context, reader *os.File, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The deadline interrupts the read once the context is done.
+	read := make(chan struct{})
+	defer close(read)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = reader.SetReadDeadline(time.Now())
+		case <-read:
+		}
+	}()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return "", fmt.Errorf("%w: %w", ErrSetupTimeout, ctx.Err())
+		}
+		return "", fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	var status entrypoint.SetupStatus
+	if err := proto.Unmarshal(data, &status); err != nil {
+		return "", fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+	return status.Error, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestContainerConfig(t *testing.T) {
+	options := &core.RunOptions{
+		Arguments: []string{"app", "--verbose"},
+		User:      &core.User{Uid: 1000, Gid: 1000},
+		Rlimits:   []*core.Rlimit{{Type: "nofile", Soft: 1024, Hard: 4096}},
+	}
+	mounts := []linux.MountSpec{{Kind: linux.MountTmpfs, Destination: "/tmp"}}
+
+	config := containerConfig("/mnt/root", &core.Application{Path: "/bin/app"}, options, capabilitySets{}, mounts, nil)
+	require.Equal(t, uint32(entrypoint.ConfigVersion), config.Version)
+	require.Equal(t, defaultHostname, config.Hostname)
+	require.Equal(t, []string{"/bin/app", "--verbose"}, config.Process.Arguments)
+	require.Equal(t, defaultWorkingDir, config.Process.WorkingDir)
+	require.True(t, config.Process.NoNewPrivileges)
+	require.True(t, proto.Equal(options.User, config.Process.User))
+
+	// The mounts of the application are made after the system ones, so they can cover them.
+	require.Len(t, config.Mounts, len(systemMounts)+1)
+	require.Equal(t, entrypoint.MountKind_MOUNT_KIND_PROC, config.Mounts[0].Kind)
+	require.Equal(t, entrypoint.MountKind_MOUNT_KIND_SYSFS, config.Mounts[1].Kind)
+	require.True(t, config.Mounts[1].ReadOnly)
+	require.Equal(t, mounts[0], config.Mounts[len(systemMounts)].Spec())
+}
+
+func TestValidateConfig(t *testing.T) {
+	require.NoError(t, validateConfig(&core.RunOptions{
+		Hostname:   "web-1.local",
+		WorkingDir: "/srv",
+		Rlimits:    []*core.Rlimit{{Type: "RLIMIT_NOFILE", Soft: 1024, Hard: 1024}},
+	}))
+
+	invalid := []*core.RunOptions{
+		{Hostname: "-web"},
+		{Hostname: "web_1"},
+		{WorkingDir: "srv"},
+		{Rlimits: []*core.Rlimit{{Type: "files", Soft: 1, Hard: 1}}},
+		{Rlimits: []*core.Rlimit{{Type: "nofile", Soft: 2, Hard: 1}}},
+		{Rlimits: []*core.Rlimit{{Type: "nofile", Soft: 1, Hard: 1}, {Type: "RLIMIT_NOFILE", Soft: 1, Hard: 1}}},
+	}
+	for _, options := range invalid {
+		require.True(t, errors.Is(validateConfig(options), ErrInvalidConfig), "%v", options)
+	}
+}
+
+func TestPathRestrictions(t *testing.T) {
+	config := containerConfig("/mnt/root", &core.Application{Path: "/bin/app"}, &core.RunOptions{}, capabilitySets{}, nil, nil)
+	require.Contains(t, config.MaskedPaths, "/proc/kcore")
+	require.Contains(t, config.ReadonlyPaths, "/proc/sys")
+
+	// The restrictions of the application replace the defaults, an empty message disables them.
+	options := &core.RunOptions{PathRestrictions: &core.PathRestrictions{MaskedPaths: []string{"/proc/cpuinfo"}}}
+	config = containerConfig("/mnt/root", &core.Application{Path: "/bin/app"}, options, capabilitySets{}, nil, nil)
+	require.Equal(t, []string{"/proc/cpuinfo"}, config.MaskedPaths)
+	require.Empty(t, config.ReadonlyPaths)
+
+	invalid := []*core.PathRestrictions{
+		{MaskedPaths: []string{"proc/kcore"}},
+		{ReadonlyPaths: []string{"/proc/../etc"}},
+		{ReadonlyPaths: []string{"/"}},
+	}
+	for _, restrictions := range invalid {
+		require.True(t, errors.Is(validateConfig(&core.RunOptions{PathRestrictions: restrictions}), ErrInvalidConfig), "%v", restrictions)
+	}
+}
+
+func TestReadSetupStatus(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	data, err := proto.Marshal(&entrypoint.SetupStatus{Error: "could not mount"})
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	status, err := readSetupStatus(context.Background(), reader, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "could not mount", status)
+
+	// An entrypoint, which never finishes the setup, does not block the start forever.
+	reader, writer, err = os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+	defer writer.Close()
+	_, err = readSetupStatus(context.Background(), reader, 10*time.Millisecond)
+	require.True(t, errors.Is(err, ErrSetupTimeout))
+}
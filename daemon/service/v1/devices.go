@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/cgroup"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+var ErrInvalidDevice = errors.New("invalid device")
+
+const defaultDeviceMode = 0600
+
+// defaultDevices are created in the /dev of every container.
+var defaultDevices = []*core.Device{
+	{Path: "/dev/null", Major: 1, Minor: 3, Mode: 0666},
+	{Path: "/dev/zero", Major: 1, Minor: 5, Mode: 0666},
+	{Path: "/dev/full", Major: 1, Minor: 7, Mode: 0666},
+	{Path: "/dev/random", Major: 1, Minor: 8, Mode: 0666},
+	{Path: "/dev/urandom", Major: 1, Minor: 9, Mode: 0666},
+	{Path: "/dev/tty", Major: 5, Minor: 0, Mode: 0666},
+	{Path: "/dev/console", Major: 5, Minor: 1, Mode: 0666},
+}
+
+const deviceAccess = cgroup.BPF_DEVCG_ACC_READ | cgroup.BPF_DEVCG_ACC_WRITE
+
+// terminalDeviceRules allow the terminals of the devpts mounted at /dev/pts, its ptmx and the Unix98 ptys.
+var terminalDeviceRules = []cgroup.DeviceRule{
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 5, Minor: 2, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 136, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 137, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 138, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 139, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 140, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 141, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 142, Minor: cgroup.AnyMinor, Access: deviceAccess},
+	{Type: cgroup.BPF_DEVCG_DEV_CHAR, Major: 143, Minor: cgroup.AnyMinor, Access: deviceAccess},
+}
+
+// deviceRules are enforced by the cgroup of the container, so it can create and open only the nodes
+// of its devices and of the terminals, even with CAP_MKNOD.
+func deviceRules(devices []*core.Device) []cgroup.DeviceRule {
+	rules := append([]cgroup.DeviceRule(nil), terminalDeviceRules...)
+	for _, device := range devices {
+		deviceType := uint32(cgroup.BPF_DEVCG_DEV_CHAR)
+		if device.Type == core.DeviceType_DEVICE_TYPE_BLOCK {
+			deviceType = cgroup.BPF_DEVCG_DEV_BLOCK
+		}
+		rules = append(rules, cgroup.DeviceRule{
+			Type:   deviceType,
+			Major:  device.Major,
+			Minor:  int64(device.Minor),
+			Access: deviceAccess | cgroup.BPF_DEVCG_ACC_MKNOD,
+		})
+	}
+	return rules
+}
+
+// devMounts make up the /dev of a container, the device nodes are created in the tmpfs.
+var devMounts = []linux.MountSpec{
+	{Kind: linux.MountTmpfs, Destination: "/dev", Mode: 0755, AllowDevices: true},
+	{Kind: linux.MountDevpts, Destination: "/dev/pts"},
+	{Kind: linux.MountTmpfs, Destination: "/dev/shm", Mode: 01777, Size: 64 << 20},
+	{Kind: linux.MountMqueue, Destination: "/dev/mqueue"},
+}
+
+// reservedDevicePaths are covered by the mounts and the symlinks of /dev.
+var reservedDevicePaths = []string{"/dev/pts", "/dev/shm", "/dev/mqueue", "/dev/ptmx", "/dev/fd", "/dev/stdin", "/dev/stdout", "/dev/stderr"}
+
+// validateDevices checks the devices declared by the application, they cannot replace the default ones.
+func validateDevices(devices []*core.Device) error {
+	paths := map[string]bool{}
+	for _, device := range defaultDevices {
+		paths[device.Path] = true
+	}
+
+	for _, device := range devices {
+		path := filepath.Clean(device.Path)
+		if err := linux.ValidateMountPath(device.Path); err != nil || !strings.HasPrefix(path, "/dev/") {
+			return fmt.Errorf("%w: %s is not inside /dev", ErrInvalidDevice, device.Path)
+		}
+		for _, reserved := range reservedDevicePaths {
+			if path == reserved || strings.HasPrefix(path, reserved+"/") {
+				return fmt.Errorf("%w: %s is reserved", ErrInvalidDevice, device.Path)
+			}
+		}
+		if paths[path] {
+			return fmt.Errorf("%w: duplicated path %s", ErrInvalidDevice, device.Path)
+		}
+		paths[path] = true
+
+		if _, ok := core.DeviceType_name[int32(device.Type)]; !ok {
+			return fmt.Errorf("%w: unknown type of %s", ErrInvalidDevice, device.Path)
+		}
+		if device.Mode > 0777 {
+			return fmt.Errorf("%w: invalid mode %o of %s", ErrInvalidDevice, device.Mode, device.Path)
+		}
+	}
+	return nil
+}
+
+// containerDevices returns the default devices followed by the devices of the application.
+func containerDevices(devices []*core.Device) []*core.Device {
+	result := make([]*core.Device, 0, len(defaultDevices)+len(devices))
+	result = append(result, defaultDevices...)
+	for _, device := range devices {
+		mode := device.Mode
+		if mode == 0 {
+			mode = defaultDeviceMode
+		}
+		result = append(result, &core.Device{
+			Path:  filepath.Clean(device.Path),
+			Type:  device.Type,
+			Major: device.Major,
+			Minor: device.Minor,
+			Mode:  mode,
+			Uid:   device.Uid,
+			Gid:   device.Gid,
+		})
+	}
+	return result
+}
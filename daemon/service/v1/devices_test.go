@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/cgroup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerDevices(t *testing.T) {
+	declared := []*core.Device{
+		{Path: "/dev/fuse", Major: 10, Minor: 229, Mode: 0666},
+		{Path: "/dev/loop//0", Type: core.DeviceType_DEVICE_TYPE_BLOCK, Major: 7, Minor: 0, Gid: 6},
+	}
+	require.NoError(t, validateDevices(declared))
+
+	devices := containerDevices(declared)
+	require.Len(t, devices, len(defaultDevices)+2)
+	require.Equal(t, "/dev/null", devices[0].Path)
+	require.Equal(t, "/dev/loop/0", devices[len(devices)-1].Path)
+	require.Equal(t, uint32(defaultDeviceMode), devices[len(devices)-1].Mode)
+	require.Equal(t, uint32(6), devices[len(devices)-1].Gid)
+
+	// Only the declared block device can be created and opened.
+	rules := deviceRules(devices)
+	require.Equal(t, cgroup.DeviceRule{
+		Type:   cgroup.BPF_DEVCG_DEV_BLOCK,
+		Major:  7,
+		Minor:  0,
+		Access: cgroup.BPF_DEVCG_ACC_MKNOD | cgroup.BPF_DEVCG_ACC_READ | cgroup.BPF_DEVCG_ACC_WRITE,
+	}, rules[len(rules)-1])
+}
+
+func TestValidateDevices_Invalid(t *testing.T) {
+	invalid := [][]*core.Device{
+		{{Path: "/tmp/null", Major: 1, Minor: 3}},
+		{{Path: "/dev/../etc/passwd", Major: 1, Minor: 3}},
+		{{Path: "/dev/null", Major: 1, Minor: 3}},
+		{{Path: "/dev/pts/0", Major: 136, Minor: 0}},
+		{{Path: "/dev/fuse", Major: 10, Minor: 229}, {Path: "/dev/fuse", Major: 10, Minor: 229}},
+		{{Path: "/dev/fuse", Type: 5, Major: 10, Minor: 229}},
+		{{Path: "/dev/fuse", Major: 10, Minor: 229, Mode: 04666}},
+	}
+
+	for _, devices := range invalid {
+		require.True(t, errors.Is(validateDevices(devices), ErrInvalidDevice), "%v", devices)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+const EtcRoot = "/opt/frama/etc"
+
+// etcFiles are the network configuration files of a container. They are bind-mounted over the files of the image,
+// so the image is never modified and its symlinks are resolved within the container root by the entrypoint.
+type etcFiles struct {
+	Path       string
+	ResolvConf string
+	Hosts      string
+}
+
+func newEtcFiles(name string) (etcFiles, error) {
+	path := filepath.Join(EtcRoot, name)
+	files := etcFiles{
+		Path:       path,
+		ResolvConf: filepath.Join(path, "resolv.conf"),
+		Hosts:      filepath.Join(path, "hosts"),
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return etcFiles{}, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	// The files are filled in once the address of the container is known.
+	for _, file := range []string{files.ResolvConf, files.Hosts} {
+		if err := os.WriteFile(file, nil, 0644); err != nil {
+			return etcFiles{}, fmt.Errorf("os.WriteFile: %w", err)
+		}
+	}
+	return files, nil
+}
+
+func (f etcFiles) mounts() []linux.MountSpec {
+	return []linux.MountSpec{
+		{Kind: linux.MountBind, Source: f.ResolvConf, Destination: "/etc/resolv.conf"},
+		{Kind: linux.MountBind, Source: f.Hosts, Destination: "/etc/hosts"},
+	}
+}
+
+func (f etcFiles) Release() error {
+	return os.RemoveAll(f.Path)
+}
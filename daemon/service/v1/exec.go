@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	entrypoint "github.com/mmbednarek/fragma/api/fragma/entrypoint/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/nsenter"
+	"github.com/mmbednarek/fragma/pkg/process"
+	"github.com/mmbednarek/fragma/pkg/protoutil"
+)
+
+var ErrInvalidExec = errors.New("invalid exec request")
+
+// containerEntrypointPath is the entrypoint binary seen from the container, the entrypoint is
+// the init of the pid namespace. Its exec stage applies the restrictions of the run to the new process.
+const containerEntrypointPath = "/proc/1/exe"
+
+const execStageArg = "exec-stage"
+
+// Exec is an additional process started inside the namespaces of a run.
+type Exec struct {
+	cmd      *exec.Cmd
+	terminal *linux.Terminal
+	copied   <-chan struct{}
+}
+
+func (e *Exec) Pid() int {
+	return e.cmd.Process.Pid
+}
+
+// Resize changes the window size of the terminal, without a terminal it does nothing.
+func (e *Exec) Resize(rows uint16, cols uint16) error {
+	if e.terminal == nil {
+		return nil
+	}
+	attr := linux.Termios{Wz: linux.Winsize{WsRow: rows, WsCol: cols}}
+	return attr.Setwinsz(e.terminal.MasterFile)
+}
+
+func (e *Exec) Kill() error {
+	return e.cmd.Process.Kill()
+}
+
+// Wait blocks until the process exits and its output is copied, it returns the exit code.
+func (e *Exec) Wait() (int32, error) {
+	err := e.cmd.Wait()
+	if e.terminal != nil {
+		<-e.copied
+		e.terminal.Close()
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return -1, fmt.Errorf("e.cmd.Wait: %w", err)
+	}
+	code, _ := exitStatus(e.cmd.ProcessState)
+	return code, nil
+}
+
+// execConfig describes the process executed by the exec stage.
+func execConfig(options *core.RunOptions, caps capabilitySets, request *core.ExecRequest, program []byte) *entrypoint.Process {
+	config := &entrypoint.Process{
+		Arguments:       request.Arguments,
+		Environment:     applicationEnv(options.Environment),
+		WorkingDir:      request.WorkingDir,
+		User:            options.User,
+		Capabilities:    caps.config(),
+		NoNewPrivileges: !options.AllowNewPrivileges,
+		SeccompProgram:  program,
+	}
+	// The process enters the user namespace of the run as the host root, so it always switches to the user.
+	if config.User == nil {
+		config.User = &core.User{}
+	}
+	if len(config.WorkingDir) == 0 {
+		config.WorkingDir = workingDir(options)
+	}
+	for key, value := range request.Environment {
+		config.Environment = append(config.Environment, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return config
+}
+
+// Exec starts a process inside all the namespaces and the root of the run, with the restrictions of the application.
+func (s *Service) Exec(ctx context.Context, name string, request *core.ExecRequest, stdio Stdio) (*Exec, error) {
+	if len(request.Arguments) == 0 {
+		return nil, fmt.Errorf("%w: no command", ErrInvalidExec)
+	}
+
+	run, err := s.FindRun(name)
+	if err != nil {
+		return nil, err
+	}
+	current := run.attempt()
+	if current == nil {
+		return nil, fmt.Errorf("%w: %s is waiting for a restart", ErrRunNotFound, name)
+	}
+	pid := current.cmd.Process.Pid
+	options := run.options
+
+	caps, err := newCapabilitySets(options.Capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("newCapabilitySets: %w", err)
+	}
+
+	program, err := seccompProgram(options.Seccomp)
+	if err != nil {
+		return nil, fmt.Errorf("seccompProgram: %w", err)
+	}
+
+	config, err := protoutil.Pipe(execConfig(options, caps, request, program))
+	if err != nil {
+		return nil, fmt.Errorf("protoutil.Pipe: %w", err)
+	}
+	defer config.Close()
+
+	groupFd, err := current.group.Open()
+	if err != nil {
+		return nil, fmt.Errorf("current.group.Open: %w", err)
+	}
+	defer syscall.Close(groupFd)
+
+	cmd := nsenter.Command(process.Pid(pid), request.Tty, []*os.File{config}, containerEntrypointPath, execStageArg, "-config-fd", strconv.Itoa(configFd))
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		UseCgroupFD: true,
+		CgroupFD:    groupFd,
+	}
+
+	result := &Exec{cmd: cmd}
+
+	var slaveFile *os.File
+	if request.Tty {
+		terminal, err := linux.NewTerminal()
+		if err != nil {
+			return nil, fmt.Errorf("linux.NewTerminal: %w", err)
+		}
+		result.terminal = &terminal
+
+		slaveFile, err = terminal.OpenSlave()
+		if err != nil {
+			terminal.Close()
+			return nil, fmt.Errorf("terminal.OpenSlave: %w", err)
+		}
+		defer slaveFile.Close()
+
+		if request.Rows != 0 && request.Cols != 0 {
+			if err := result.Resize(uint16(request.Rows), uint16(request.Cols)); err != nil {
+				terminal.Close()
+				return nil, fmt.Errorf("result.Resize: %w", err)
+			}
+		}
+
+		cmd.Stdin = slaveFile
+		cmd.Stdout = slaveFile
+		cmd.Stderr = slaveFile
+	} else {
+		cmd.Stdin = stdio.Stdin
+		cmd.Stdout = stdio.Stdout
+		cmd.Stderr = stdio.Stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		if result.terminal != nil {
+			result.terminal.Close()
+		}
+		return nil, fmt.Errorf("cmd.Start: %w", err)
+	}
+
+	if result.terminal != nil {
+		stdout := stdio.Stdout
+		if stdout == nil {
+			stdout = io.Discard
+		}
+		result.copied = result.terminal.Pump(stdio.Stdin, stdout)
+	}
+
+	log.With(ctx, "process", name, "pid", cmd.Process.Pid, "args", request.Arguments).Info("started exec")
+	return result, nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/network"
+)
+
+type deviceNumber struct {
+	major uint32
+	minor uint32
+}
+
+// usedDevices returns the devices mounted in the mount namespaces of the other processes, such as the containers.
+func usedDevices() (map[deviceNumber]bool, error) {
+	self, err := os.Readlink("/proc/self/ns/mnt")
+	if err != nil {
+		return nil, fmt.Errorf("os.Readlink: %w", err)
+	}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	namespaces := map[string]bool{self: true}
+	devices := map[deviceNumber]bool{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// The process could have exited in the meantime.
+		namespace, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/mnt", pid))
+		if err != nil || namespaces[namespace] {
+			continue
+		}
+		namespaces[namespace] = true
+
+		mounts, err := linux.ReadMountInfo(pid)
+		if err != nil {
+			continue
+		}
+		for _, mount := range mounts {
+			devices[deviceNumber{major: mount.Major, minor: mount.Minor}] = true
+		}
+	}
+	return devices, nil
+}
+
+// overlayLowerDirs returns the lower directories of an overlay mount.
+func overlayLowerDirs(mount linux.MountInfo) []string {
+	var dirs []string
+	for _, option := range strings.Split(mount.SuperOptions, ",") {
+		if value, ok := strings.CutPrefix(option, "lowerdir="); ok {
+			for _, dir := range strings.Split(value, ":") {
+				dirs = append(dirs, filepath.Clean(dir))
+			}
+		}
+	}
+	return dirs
+}
+
+// gcPlan lists the resources, which are no longer used, and the journal entries, which are stale.
+type gcPlan struct {
+	mounts []*core.GcResource
+	loops  []*core.GcResource
+	stale  []journalEntry
+}
+
+// planGarbageCollection finds the mounts under the mount root and the loop devices recorded in the journal or backing
+// these mounts, which are neither owned by a live run nor used by any other mount namespace.
+func planGarbageCollection(mounts []linux.MountInfo, loops []linux.LoopDevice, entries []journalEntry, used map[deviceNumber]bool, live map[string]bool) gcPlan {
+	var plan gcPlan
+
+	owners := map[string]string{}
+	for _, entry := range entries {
+		if entry.Kind == core.GcResourceKind_GC_RESOURCE_KIND_MOUNT {
+			owners[entry.Path] = entry.Run
+		}
+	}
+
+	// The overlays are mounted after their lower directories, so the mounts are visited in the reverse order.
+	mounted := map[string]bool{}
+	keptDirs := map[string]bool{}
+	keptDevices := map[deviceNumber]bool{}
+	var loopSources []journalEntry
+	for i := len(mounts) - 1; i >= 0; i-- {
+		mount := mounts[i]
+		device := deviceNumber{major: mount.Major, minor: mount.Minor}
+		mounted[mount.MountPoint] = true
+
+		// Only the mounts below the mount root are owned, not the directories sharing its prefix.
+		owned := strings.HasPrefix(mount.MountPoint, filepath.Clean(linux.MountRoot)+"/")
+		run := owners[mount.MountPoint]
+		if !owned || live[run] || used[device] || keptDirs[mount.MountPoint] {
+			keptDevices[device] = true
+			if mount.FsType == "overlay" {
+				for _, dir := range overlayLowerDirs(mount) {
+					keptDirs[dir] = true
+				}
+			}
+			continue
+		}
+
+		plan.mounts = append(plan.mounts, &core.GcResource{
+			Kind: core.GcResourceKind_GC_RESOURCE_KIND_MOUNT,
+			Path: mount.MountPoint,
+			Run:  run,
+		})
+		if strings.HasPrefix(mount.Source, "/dev/loop") {
+			loopSources = append(loopSources, journalEntry{Kind: core.GcResourceKind_GC_RESOURCE_KIND_LOOP, Path: mount.Source, Run: run})
+		}
+	}
+
+	attached := map[string]linux.LoopDevice{}
+	for _, loop := range loops {
+		attached[loop.Path] = loop
+	}
+	collected := map[string]bool{}
+	collectLoop := func(path string, run string, backingFile string) {
+		loop, ok := attached[path]
+		if !ok || collected[path] || live[run] {
+			return
+		}
+		// The device was detached and then reused by someone else.
+		if len(backingFile) != 0 && loop.BackingFile != backingFile {
+			return
+		}
+		// The device could also be mounted directly by another mount namespace.
+		device := deviceNumber{major: loop.Major, minor: loop.Minor}
+		if keptDevices[device] || used[device] {
+			return
+		}
+		collected[path] = true
+		plan.loops = append(plan.loops, &core.GcResource{
+			Kind:        core.GcResourceKind_GC_RESOURCE_KIND_LOOP,
+			Path:        path,
+			Run:         run,
+			BackingFile: loop.BackingFile,
+		})
+	}
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case core.GcResourceKind_GC_RESOURCE_KIND_MOUNT:
+			if !mounted[entry.Path] {
+				plan.stale = append(plan.stale, entry)
+			}
+		case core.GcResourceKind_GC_RESOURCE_KIND_LOOP:
+			loop, ok := attached[entry.Path]
+			if !ok || loop.BackingFile != entry.BackingFile {
+				plan.stale = append(plan.stale, entry)
+				continue
+			}
+			collectLoop(entry.Path, entry.Run, entry.BackingFile)
+		}
+	}
+	for _, source := range loopSources {
+		collectLoop(source.Path, source.Run, "")
+	}
+	return plan
+}
+
+// networkResource describes a leaked host interface or address.
+func networkResource(leak network.Leak) *core.GcResource {
+	if len(leak.Interface) != 0 {
+		return &core.GcResource{Kind: core.GcResourceKind_GC_RESOURCE_KIND_INTERFACE, Path: leak.Interface, Run: leak.Owner}
+	}
+	return &core.GcResource{Kind: core.GcResourceKind_GC_RESOURCE_KIND_ADDRESS, Path: leak.Address.String(), Run: leak.Owner}
+}
+
+// CollectGarbage releases the mounts, the loop devices and the network resources left behind by the runs, which no
+// longer exist, for example after a crash of the daemon. The resources used by the live runs or the containers are kept.
+func (s *Service) CollectGarbage(ctx context.Context, dryRun bool) ([]*core.GcResource, error) {
+	s.attaching.Lock()
+	defer s.attaching.Unlock()
+
+	mounts, err := linux.ReadMountInfo(0)
+	if err != nil {
+		return nil, fmt.Errorf("linux.ReadMountInfo: %w", err)
+	}
+	loops, err := linux.LoopList()
+	if err != nil {
+		return nil, fmt.Errorf("linux.LoopList: %w", err)
+	}
+	used, err := usedDevices()
+	if err != nil {
+		return nil, fmt.Errorf("usedDevices: %w", err)
+	}
+
+	live := map[string]bool{}
+	s.mu.Lock()
+	for name := range s.runs {
+		live[name] = true
+	}
+	s.mu.Unlock()
+	// The shared images are kept until their idle timeout.
+	for _, owner := range s.images.owners() {
+		live[owner] = true
+	}
+
+	plan := planGarbageCollection(mounts, loops, s.journal.snapshot(), used, live)
+	resources := append(plan.mounts, plan.loops...)
+	if s.network != nil {
+		leaks, err := s.network.FindLeaks(live)
+		if err != nil {
+			return nil, fmt.Errorf("s.network.FindLeaks: %w", err)
+		}
+		for _, leak := range leaks {
+			resources = append(resources, networkResource(leak))
+		}
+	}
+	if dryRun {
+		return resources, nil
+	}
+
+	for _, entry := range plan.stale {
+		if err := s.journal.forget(entry.Kind, entry.Path); err != nil {
+			return nil, fmt.Errorf("s.journal.forget: %w", err)
+		}
+	}
+	// The mounts are released before the loop devices backing them.
+	for _, resource := range resources {
+		var err error
+		switch resource.Kind {
+		case core.GcResourceKind_GC_RESOURCE_KIND_MOUNT:
+			err = s.journal.unmount(linux.Mount{Path: resource.Path})
+			if err == nil {
+				// The mount point is kept if it is not empty.
+				if err := os.Remove(resource.Path); err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOTEMPTY) {
+					log.With(ctx, "mount", resource.Path, "msg", err).Warn("could not remove mount point")
+				}
+			}
+		case core.GcResourceKind_GC_RESOURCE_KIND_LOOP:
+			err = s.journal.detachLoop(resource.Path)
+		case core.GcResourceKind_GC_RESOURCE_KIND_INTERFACE:
+			err = s.network.ReleaseLeak(network.Leak{Owner: resource.Run, Interface: resource.Path})
+		case core.GcResourceKind_GC_RESOURCE_KIND_ADDRESS:
+			err = s.network.ReleaseLeak(network.Leak{Owner: resource.Run, Address: net.ParseIP(resource.Path)})
+		}
+		if err != nil {
+			resource.Error = err.Error()
+		}
+	}
+	return resources, nil
+}
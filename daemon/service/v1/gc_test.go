@@ -0,0 +1,81 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanGarbageCollection(t *testing.T) {
+	mount := core.GcResourceKind_GC_RESOURCE_KIND_MOUNT
+	loop := core.GcResourceKind_GC_RESOURCE_KIND_LOOP
+
+	mounts := []linux.MountInfo{
+		{MountPoint: "/", Major: 259, Minor: 2, FsType: "ext4", Source: "/dev/nvme0n1p2"},
+		// The image and the overlay of a run, which is gone.
+		{MountPoint: "/opt/frama/mount/leaked", Major: 7, Minor: 0, FsType: "ext4", Source: "/dev/loop0"},
+		{MountPoint: "/opt/frama/mount/leakedov", Major: 0, Minor: 40, FsType: "overlay", Source: "overlay", SuperOptions: "rw,lowerdir=/opt/frama/mount/leaked/"},
+		// The overlay is still used by a container, which survived the daemon.
+		{MountPoint: "/opt/frama/mount/used", Major: 7, Minor: 1, FsType: "ext4", Source: "/dev/loop1"},
+		{MountPoint: "/opt/frama/mount/usedov", Major: 0, Minor: 41, FsType: "overlay", Source: "overlay", SuperOptions: "rw,lowerdir=/opt/frama/mount/used/"},
+		// The volume of a live run.
+		{MountPoint: "/opt/frama/mount/live", Major: 7, Minor: 2, FsType: "ext4", Source: "/dev/loop2"},
+		// Only shares the prefix of the mount root.
+		{MountPoint: "/opt/frama/mountpoint", Major: 7, Minor: 6, FsType: "ext4", Source: "/dev/loop6"},
+	}
+	loops := []linux.LoopDevice{
+		{Path: "/dev/loop0", Major: 7, Minor: 0, BackingFile: "/images/a"},
+		{Path: "/dev/loop1", Major: 7, Minor: 1, BackingFile: "/images/b"},
+		{Path: "/dev/loop2", Major: 7, Minor: 2, BackingFile: "/images/c"},
+		{Path: "/dev/loop3", Major: 7, Minor: 3, BackingFile: "/images/d"},
+		{Path: "/dev/loop4", Major: 7, Minor: 4, BackingFile: "/other/e"},
+		{Path: "/dev/loop5", Major: 7, Minor: 5, BackingFile: "/images/f"},
+		{Path: "/dev/loop6", Major: 7, Minor: 6, BackingFile: "/images/g"},
+	}
+	entries := []journalEntry{
+		{Kind: loop, Path: "/dev/loop0", Run: "gone", BackingFile: "/images/a"},
+		{Kind: mount, Path: "/opt/frama/mount/leaked", Run: "gone"},
+		{Kind: mount, Path: "/opt/frama/mount/leakedov", Run: "gone"},
+		{Kind: loop, Path: "/dev/loop2", Run: "live", BackingFile: "/images/c"},
+		{Kind: mount, Path: "/opt/frama/mount/live", Run: "live"},
+		// The loop device was attached, but the image was never mounted.
+		{Kind: loop, Path: "/dev/loop3", Run: "failed", BackingFile: "/images/d"},
+		// The loop device was detached and reused by someone else.
+		{Kind: loop, Path: "/dev/loop4", Run: "old", BackingFile: "/images/e"},
+		{Kind: mount, Path: "/opt/frama/mount/unmounted", Run: "old"},
+		// The loop device is only mounted by a container, which survived the daemon.
+		{Kind: loop, Path: "/dev/loop5", Run: "gone", BackingFile: "/images/f"},
+	}
+	used := map[deviceNumber]bool{{major: 0, minor: 41}: true, {major: 7, minor: 5}: true}
+	live := map[string]bool{"live": true}
+
+	plan := planGarbageCollection(mounts, loops, entries, used, live)
+	require.Equal(t, []*core.GcResource{
+		{Kind: mount, Path: "/opt/frama/mount/leakedov", Run: "gone"},
+		{Kind: mount, Path: "/opt/frama/mount/leaked", Run: "gone"},
+	}, plan.mounts)
+	require.Equal(t, []*core.GcResource{
+		{Kind: loop, Path: "/dev/loop0", Run: "gone", BackingFile: "/images/a"},
+		{Kind: loop, Path: "/dev/loop3", Run: "failed", BackingFile: "/images/d"},
+	}, plan.loops)
+	require.Equal(t, []journalEntry{entries[6], entries[7]}, plan.stale)
+}
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	journal, err := OpenJournal(path)
+	require.NoError(t, err)
+
+	entry := journalEntry{Kind: core.GcResourceKind_GC_RESOURCE_KIND_MOUNT, Path: "/opt/frama/mount/abc", Run: "web"}
+	require.NoError(t, journal.record(entry))
+	require.NoError(t, journal.record(journalEntry{Kind: core.GcResourceKind_GC_RESOURCE_KIND_LOOP, Path: "/dev/loop0", Run: "web"}))
+	require.NoError(t, journal.forget(core.GcResourceKind_GC_RESOURCE_KIND_LOOP, "/dev/loop0"))
+
+	// The journal survives a restart of the daemon.
+	reopened, err := OpenJournal(path)
+	require.NoError(t, err)
+	require.Equal(t, []journalEntry{entry}, reopened.snapshot())
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/log"
+)
+
+// ErrVolumeInUse is returned if a volume cannot be mounted for writing, because other runs share its read-only mount.
+var ErrVolumeInUse = errors.New("volume is used by other runs")
+
+// DefaultImageIdleTimeout is how long an image stays mounted after the last run using it exits.
+const DefaultImageIdleTimeout = time.Minute
+
+// fileKey identifies the image file by its inode, so the different paths of the same file share the mount.
+type fileKey struct {
+	device uint64
+	inode  uint64
+}
+
+// imageKey identifies a mount of the image file. The size and the change time tell apart the contents
+// of a file modified in place, so a stale mount is never reused.
+type imageKey struct {
+	file    fileKey
+	size    int64
+	changed syscall.Timespec
+	fsType  string
+	data    string
+}
+
+// sharedImage is a read-only mount of an image file, the runs layer their own overlays on top of it.
+type sharedImage struct {
+	key imageKey
+	// owner is recorded in the journal in place of the run name.
+	owner  string
+	volume *volumeMount
+	refs   int
+	// released counts the releases of the last reference, it tells apart the stale eviction timers.
+	released int
+	// ready is closed once the image is mounted, err is set if it could not be.
+	ready chan struct{}
+	err   error
+}
+
+// imageManager keeps one read-only mount per image file, which is shared by the concurrent runs.
+// The mount is released once it is not used for the idle timeout.
+type imageManager struct {
+	journal     *Journal
+	idleTimeout time.Duration
+	// attaching is held while an idle image is released, so it is not collected at the same time.
+	attaching *sync.RWMutex
+
+	mu     sync.Mutex
+	images map[imageKey]*sharedImage
+	// writers are the image files mounted for writing, they cannot be shared at the same time.
+	writers map[fileKey]bool
+}
+
+// writableImage is an exclusive writable mount of an image file.
+type writableImage struct {
+	file   fileKey
+	volume *volumeMount
+}
+
+func newImageManager(journal *Journal, idleTimeout time.Duration, attaching *sync.RWMutex) *imageManager {
+	return &imageManager{
+		journal:     journal,
+		idleTimeout: idleTimeout,
+		attaching:   attaching,
+		images:      map[imageKey]*sharedImage{},
+		writers:     map[fileKey]bool{},
+	}
+}
+
+func newFileKey(path string) (fileKey, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return fileKey{}, fmt.Errorf("syscall.Stat: %w", err)
+	}
+	return fileKey{device: stat.Dev, inode: stat.Ino}, nil
+}
+
+func newImageKey(path string, device linux.DeviceOptions) (imageKey, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return imageKey{}, fmt.Errorf("syscall.Stat: %w", err)
+	}
+	return imageKey{
+		file:    fileKey{device: stat.Dev, inode: stat.Ino},
+		size:    stat.Size,
+		changed: stat.Ctim,
+		fsType:  device.FsType,
+		data:    device.Data,
+	}, nil
+}
+
+// imageOwner is never a valid run name, so the shared images cannot be mistaken for the resources of a run.
+func imageOwner(path string) string {
+	return "image:" + path
+}
+
+// acquire returns the shared mount of the image, the device has to be read-only.
+// The image has to be released once the run no longer uses it.
+func (m *imageManager) acquire(path string, device linux.DeviceOptions) (*sharedImage, error) {
+	key, err := newImageKey(path, device)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.writers[key.file] {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is mounted for writing", ErrVolumeInUse, path)
+	}
+	if image, ok := m.images[key]; ok {
+		image.refs++
+		m.mu.Unlock()
+
+		// Another run could be mounting the image right now.
+		<-image.ready
+		if image.err != nil {
+			return nil, image.err
+		}
+		return image, nil
+	}
+	image := &sharedImage{key: key, owner: imageOwner(path), refs: 1, ready: make(chan struct{})}
+	m.images[key] = image
+	m.mu.Unlock()
+
+	image.volume, image.err = mountVolume(m.journal, image.owner, path, device)
+	close(image.ready)
+	if image.err != nil {
+		m.mu.Lock()
+		delete(m.images, key)
+		m.mu.Unlock()
+		return nil, image.err
+	}
+	return image, nil
+}
+
+func (m *imageManager) release(image *sharedImage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	image.refs--
+	if image.refs == 0 {
+		image.released++
+		released := image.released
+		time.AfterFunc(m.idleTimeout, func() { m.evict(image, released) })
+	}
+}
+
+// evict releases the image, unless it was acquired again in the meantime.
+func (m *imageManager) evict(image *sharedImage, released int) {
+	m.attaching.RLock()
+	defer m.attaching.RUnlock()
+
+	m.mu.Lock()
+	if image.refs > 0 || image.released != released || m.images[image.key] != image {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.images, image.key)
+	m.mu.Unlock()
+
+	if err := image.volume.release(); err != nil {
+		log.With(context.Background(), "mount", image.volume.mount.Path, "msg", err).Warn("could not release image")
+	}
+}
+
+// takeIdle removes the idle shared mounts of the image file, which have to be released by the caller.
+// It fails if the file is in use, m.mu has to be held.
+func (m *imageManager) takeIdle(path string, file fileKey) ([]*sharedImage, error) {
+	if m.writers[file] {
+		return nil, fmt.Errorf("%w: %s is mounted for writing", ErrVolumeInUse, path)
+	}
+	var idle []*sharedImage
+	for key, image := range m.images {
+		if key.file != file {
+			continue
+		}
+		if image.refs > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrVolumeInUse, path)
+		}
+		idle = append(idle, image)
+	}
+	for _, image := range idle {
+		delete(m.images, image.key)
+	}
+	return idle, nil
+}
+
+func releaseImages(images []*sharedImage) error {
+	for _, image := range images {
+		if err := image.volume.release(); err != nil {
+			return fmt.Errorf("image.volume.release: %w", err)
+		}
+	}
+	return nil
+}
+
+// evictFile releases the idle shared mounts of the image file, it fails if the file is in use.
+func (m *imageManager) evictFile(path string) error {
+	file, err := newFileKey(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	idle, err := m.takeIdle(path, file)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return releaseImages(idle)
+}
+
+// mountWritable releases the idle shared mounts of the image file and mounts it for writing. Until the mount
+// is released with releaseWritable, the file can neither be shared nor mounted for writing again.
+// A filesystem mounted through two loop devices at once, with one of them writable, could be corrupted.
+func (m *imageManager) mountWritable(run string, path string, device linux.DeviceOptions) (*writableImage, error) {
+	file, err := newFileKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	idle, err := m.takeIdle(path, file)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	m.writers[file] = true
+	m.mu.Unlock()
+
+	image := &writableImage{file: file}
+	if err := releaseImages(idle); err != nil {
+		m.releaseWriter(file)
+		return nil, err
+	}
+	image.volume, err = mountVolume(m.journal, run, path, device)
+	if err != nil {
+		m.releaseWriter(file)
+		return nil, fmt.Errorf("mountVolume: %w", err)
+	}
+	return image, nil
+}
+
+func (m *imageManager) releaseWriter(file fileKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.writers, file)
+}
+
+// releaseWritable releases the writable mount, the file can be shared again afterwards.
+func (m *imageManager) releaseWritable(image *writableImage) error {
+	defer m.releaseWriter(image.file)
+	return image.volume.release()
+}
+
+// owners returns the journal owners of the mounted images.
+func (m *imageManager) owners() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owners := make([]string, 0, len(m.images))
+	for _, image := range m.images {
+		owners = append(owners, image.owner)
+	}
+	return owners
+}
+
+// close releases the images, which are no longer used, without waiting for their idle timeout.
+func (m *imageManager) close(ctx context.Context) {
+	m.mu.Lock()
+	var idle []*sharedImage
+	for key, image := range m.images {
+		if image.refs == 0 {
+			idle = append(idle, image)
+			delete(m.images, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, image := range idle {
+		if err := image.volume.release(); err != nil {
+			log.With(ctx, "mount", image.volume.mount.Path, "msg", err).Warn("could not release image")
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/stretchr/testify/require"
+)
+
+// testImage creates an empty ext4 image, the test is skipped if the loop devices cannot be used.
+func testImage(tb testing.TB) string {
+	if syscall.Access("/dev/loop-control", syscall.O_RDWR) != nil {
+		tb.Skip("loop devices are not available")
+	}
+	mkfs, err := exec.LookPath("mkfs.ext4")
+	if err != nil {
+		tb.Skip("mkfs.ext4 is not available")
+	}
+
+	path := filepath.Join(tb.TempDir(), "image")
+	require.NoError(tb, os.WriteFile(path, nil, 0644))
+	require.NoError(tb, os.Truncate(path, 16<<20))
+	require.NoError(tb, exec.Command(mkfs, "-q", path).Run())
+	return path
+}
+
+func TestImageManager(t *testing.T) {
+	path := testImage(t)
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(path, link))
+
+	var attaching sync.RWMutex
+	manager := newImageManager(nil, 10*time.Millisecond, &attaching)
+	device := linux.DeviceOptions{FsType: linux.FilesystemExt4, ReadOnly: true}
+
+	// The runs share the mount, also when they refer to the image by another path.
+	first, err := manager.acquire(path, device)
+	require.NoError(t, err)
+	second, err := manager.acquire(link, device)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	manager.release(first)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, manager.owners(), 1)
+
+	// The image is released after the idle timeout of the last run.
+	manager.release(second)
+	require.Eventually(t, func() bool { return len(manager.owners()) == 0 }, time.Second, 10*time.Millisecond)
+	_, err = linux.LoopStatus(first.volume.loopPath)
+	require.Error(t, err)
+}
+
+func TestImageManager_ModifiedFile(t *testing.T) {
+	path := testImage(t)
+
+	var attaching sync.RWMutex
+	manager := newImageManager(nil, time.Minute, &attaching)
+	device := linux.DeviceOptions{FsType: linux.FilesystemExt4, ReadOnly: true}
+	first, err := manager.acquire(path, device)
+	require.NoError(t, err)
+	manager.release(first)
+
+	// The mount of the previous contents is not reused.
+	require.NoError(t, os.Truncate(path, 32<<20))
+	second, err := manager.acquire(path, device)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	manager.release(second)
+	manager.close(context.Background())
+}
+
+func TestImageManager_EvictFile(t *testing.T) {
+	path := testImage(t)
+
+	var attaching sync.RWMutex
+	manager := newImageManager(nil, time.Minute, &attaching)
+	image, err := manager.acquire(path, linux.DeviceOptions{FsType: linux.FilesystemExt4, ReadOnly: true})
+	require.NoError(t, err)
+
+	// The image cannot be mounted for writing while the runs use it, the idle mount is released right away.
+	require.True(t, errors.Is(manager.evictFile(path), ErrVolumeInUse))
+	manager.release(image)
+	require.NoError(t, manager.evictFile(path))
+	require.Empty(t, manager.owners())
+}
+
+func TestImageManager_MountWritable(t *testing.T) {
+	path := testImage(t)
+
+	var attaching sync.RWMutex
+	manager := newImageManager(nil, time.Minute, &attaching)
+	shared := linux.DeviceOptions{FsType: linux.FilesystemExt4, ReadOnly: true}
+	image, err := manager.acquire(path, shared)
+	require.NoError(t, err)
+	_, err = manager.mountWritable("test", path, linux.DeviceOptions{FsType: linux.FilesystemExt4})
+	require.True(t, errors.Is(err, ErrVolumeInUse))
+
+	// The idle shared mount is released, the file cannot be shared or written by another run meanwhile.
+	manager.release(image)
+	writable, err := manager.mountWritable("test", path, linux.DeviceOptions{FsType: linux.FilesystemExt4})
+	require.NoError(t, err)
+	require.Empty(t, manager.owners())
+	_, err = manager.acquire(path, shared)
+	require.True(t, errors.Is(err, ErrVolumeInUse))
+	_, err = manager.mountWritable("test", path, linux.DeviceOptions{FsType: linux.FilesystemExt4})
+	require.True(t, errors.Is(err, ErrVolumeInUse))
+
+	require.NoError(t, manager.releaseWritable(writable))
+	image, err = manager.acquire(path, shared)
+	require.NoError(t, err)
+	manager.release(image)
+	manager.close(context.Background())
+}
+
+// BenchmarkRootfs compares preparing the root of a run from an exclusive mount of the image,
+// with preparing it from the shared mount.
+func BenchmarkRootfs(b *testing.B) {
+	path := testImage(b)
+	ctx := context.Background()
+	options := &core.RunOptions{Overlay: true}
+	volume := &core.Volume{Path: path}
+
+	b.Run("exclusive", func(b *testing.B) {
+		s := NewService()
+		for i := 0; i < b.N; i++ {
+			var cleanup cleanupStack
+			mount, err := attachVolume(nil, "bench", volume, true)
+			require.NoError(b, err)
+			cleanup.push(func() { require.NoError(b, mount.release()) })
+			_, err = s.mountOverlay(ctx, "bench", mount.mount.Path, options, &cleanup)
+			require.NoError(b, err)
+			cleanup.run()
+		}
+	})
+
+	b.Run("shared", func(b *testing.B) {
+		s := NewService()
+		device, err := volumeDevice(volume, true)
+		require.NoError(b, err)
+		for i := 0; i < b.N; i++ {
+			var cleanup cleanupStack
+			image, err := s.images.acquire(path, device)
+			require.NoError(b, err)
+			cleanup.push(func() { s.images.release(image) })
+			_, err = s.mountOverlay(ctx, "bench", image.volume.mount.Path, options, &cleanup)
+			require.NoError(b, err)
+			cleanup.run()
+		}
+		b.StopTimer()
+		s.images.close(ctx)
+	})
+}
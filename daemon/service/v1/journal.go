@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+// journalEntry is a loop device or a mount, which has to be released if the daemon crashes.
+type journalEntry struct {
+	Kind core.GcResourceKind `json:"kind"`
+	Path string              `json:"path"`
+	Run  string              `json:"run"`
+	// BackingFile tells apart the loop device from the one reused by someone else.
+	BackingFile string `json:"backing_file,omitempty"`
+}
+
+// Journal persists the resources attached by the runs, so they can be released after a crash of the daemon.
+// A nil Journal records nothing.
+type Journal struct {
+	path string
+
+	mu      sync.Mutex
+	entries []journalEntry
+}
+
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+	if err := json.Unmarshal(data, &j.entries); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return j, nil
+}
+
+// save replaces the journal atomically, a crash never leaves it partially written.
+func (j *Journal) save() error {
+	data, err := json.Marshal(j.entries)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+	return nil
+}
+
+func (j *Journal) record(entry journalEntry) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, entry)
+	return j.save()
+}
+
+func (j *Journal) forget(kind core.GcResourceKind, path string) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := j.entries[:0]
+	for _, entry := range j.entries {
+		if entry.Kind != kind || entry.Path != path {
+			entries = append(entries, entry)
+		}
+	}
+	j.entries = entries
+	return j.save()
+}
+
+func (j *Journal) snapshot() []journalEntry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]journalEntry(nil), j.entries...)
+}
+
+// attachLoop attaches the file to a loop device, which is recorded in the journal.
+func (j *Journal) attachLoop(run string, file string, readOnly bool) (string, error) {
+	loopPath, err := linux.LoopSetupDevice(file, readOnly)
+	if err != nil {
+		return "", fmt.Errorf("linux.LoopSetupDevice: %w", err)
+	}
+
+	// The kernel reports the resolved path of the backing file.
+	backingFile, err := filepath.EvalSymlinks(file)
+	if err == nil {
+		backingFile, err = filepath.Abs(backingFile)
+	}
+	if err == nil {
+		err = j.record(journalEntry{Kind: core.GcResourceKind_GC_RESOURCE_KIND_LOOP, Path: loopPath, Run: run, BackingFile: backingFile})
+	}
+	if err != nil {
+		_ = linux.LoopClear(loopPath)
+		return "", err
+	}
+	return loopPath, nil
+}
+
+func (j *Journal) detachLoop(loopPath string) error {
+	if err := linux.LoopClear(loopPath); err != nil {
+		return fmt.Errorf("linux.LoopClear: %w", err)
+	}
+	return j.forget(core.GcResourceKind_GC_RESOURCE_KIND_LOOP, loopPath)
+}
+
+// recordMount records the mount in the journal, the mount is reverted if it cannot be recorded.
+func (j *Journal) recordMount(run string, mount linux.Mount) error {
+	if err := j.record(journalEntry{Kind: core.GcResourceKind_GC_RESOURCE_KIND_MOUNT, Path: filepath.Clean(mount.Path), Run: run}); err != nil {
+		_ = mount.Unmount()
+		return err
+	}
+	return nil
+}
+
+func (j *Journal) unmount(mount linux.Mount) error {
+	if err := mount.Unmount(); err != nil {
+		return fmt.Errorf("mount.Unmount: %w", err)
+	}
+	return j.forget(core.GcResourceKind_GC_RESOURCE_KIND_MOUNT, filepath.Clean(mount.Path))
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/logfile"
+)
+
+// runLog stores the output of a run, it is kept across the restarts of the run.
+type runLog struct {
+	writer *logfile.Writer
+	stdout io.WriteCloser
+	stderr io.WriteCloser
+}
+
+func (l *runLog) Close() error {
+	_ = l.stdout.Close()
+	_ = l.stderr.Close()
+	return l.writer.Close()
+}
+
+// WithLogDir stores the output of the runs in the directory, without it the output is not kept.
+func WithLogDir(dir string) func(s *Service) {
+	return func(s *Service) {
+		s.logDir = dir
+	}
+}
+
+func (s *Service) logPath(name string) string {
+	return filepath.Join(s.logDir, name+".log")
+}
+
+// bestEffortWriter ignores the errors of the log, for example once the disk is full,
+// so the output still reaches the stdio of the run. Only the first error is reported.
+type bestEffortWriter struct {
+	name   string
+	writer io.Writer
+	failed atomic.Bool
+}
+
+func (w *bestEffortWriter) Write(data []byte) (int, error) {
+	if _, err := w.writer.Write(data); err != nil && w.failed.CompareAndSwap(false, true) {
+		log.With(context.Background(), "process", w.name, "msg", err).Warn("could not write log")
+	}
+	return len(data), nil
+}
+
+func teeWriter(name string, writer io.Writer, logWriter io.Writer) io.Writer {
+	bestEffort := &bestEffortWriter{name: name, writer: logWriter}
+	if writer == nil {
+		return bestEffort
+	}
+	return io.MultiWriter(writer, bestEffort)
+}
+
+// openLog starts the log of the run and connects it to the output of the application.
+func (s *Service) openLog(run *Run) error {
+	if len(s.logDir) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.logDir, 0750); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	writer, err := logfile.Create(s.logPath(run.Name), logfile.DefaultRotation)
+	if err != nil {
+		return fmt.Errorf("logfile.Create: %w", err)
+	}
+	run.log = &runLog{
+		writer: writer,
+		stdout: writer.Stream(logfile.StreamStdout),
+		stderr: writer.Stream(logfile.StreamStderr),
+	}
+	run.stdio.Stdout = teeWriter(run.Name, run.stdio.Stdout, run.log.stdout)
+	run.stdio.Stderr = teeWriter(run.Name, run.stdio.Stderr, run.log.stderr)
+	return nil
+}
+
+// CheckLogs returns ErrRunNotFound if there are no logs of the process.
+func (s *Service) CheckLogs(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+	if len(s.logDir) == 0 {
+		return fmt.Errorf("%w: logs are not stored", ErrRunNotFound)
+	}
+	if _, err := os.Stat(s.logPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: no logs of %s", ErrRunNotFound, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Logs reads the output of the process, the log of a process is kept after it exits until
+// a process with the same name is started. Following the log of an exited process returns immediately.
+func (s *Service) Logs(ctx context.Context, name string, options logfile.ReadOptions, fn func(logfile.Record) error) error {
+	if err := s.CheckLogs(name); err != nil {
+		return err
+	}
+
+	if options.Follow {
+		run, err := s.FindRun(name)
+		if err != nil {
+			options.Follow = false
+		} else {
+			options.Done = run.Done()
+		}
+	}
+
+	err := logfile.Read(ctx, s.logPath(name), logfile.DefaultRotation, options, fn)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: no logs of %s", ErrRunNotFound, name)
+	}
+	return err
+}
+
+func (r *Run) closeLog(ctx context.Context) {
+	if r.log == nil {
+		return
+	}
+	if err := r.log.Close(); err != nil {
+		log.With(ctx, "process", r.Name, "msg", err).Warn("could not close log")
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestTeeWriter(t *testing.T) {
+	// The output is forwarded even if the log cannot be written.
+	var output bytes.Buffer
+	writer := teeWriter("test", &output, failingWriter{})
+	for i := 0; i < 2; i++ {
+		n, err := writer.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+	}
+	require.Equal(t, "line\nline\n", output.String())
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/log"
+)
+
+var (
+	ErrInvalidMount  = errors.New("invalid mount")
+	ErrInvalidVolume = errors.New("invalid volume")
+)
+
+func mountDepth(path string) int {
+	return strings.Count(filepath.Clean(path), "/")
+}
+
+// validateMounts checks the paths before anything is set up.
+func validateMounts(mounts []*core.Mount) error {
+	for _, mount := range mounts {
+		if err := linux.ValidateMountPath(mount.Destination); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMount, err)
+		}
+		if filepath.Clean(mount.Destination) == "/" {
+			return fmt.Errorf("%w: cannot mount over the root", ErrInvalidMount)
+		}
+
+		switch source := mount.Source.(type) {
+		case *core.Mount_Bind:
+			if err := linux.ValidateMountPath(source.Bind.Source); err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidMount, err)
+			}
+			if _, err := os.Stat(source.Bind.Source); err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidMount, err)
+			}
+		case *core.Mount_Tmpfs:
+			if source.Tmpfs.Mode > 07777 {
+				return fmt.Errorf("%w: invalid tmpfs mode %o", ErrInvalidMount, source.Tmpfs.Mode)
+			}
+		case *core.Mount_Volume:
+			if source.Volume == nil || len(source.Volume.Path) == 0 {
+				return fmt.Errorf("%w: volume of %s has no path", ErrInvalidMount, mount.Destination)
+			}
+		default:
+			return fmt.Errorf("%w: %s has no source", ErrInvalidMount, mount.Destination)
+		}
+	}
+	return nil
+}
+
+// volumeDevice resolves how the volume image is mounted, its filesystem is detected unless it is set explicitly.
+func volumeDevice(volume *core.Volume, readOnly bool) (linux.DeviceOptions, error) {
+	fsType := volume.FsType
+	if len(fsType) == 0 {
+		detected, err := linux.DetectFilesystem(volume.Path)
+		if err != nil {
+			return linux.DeviceOptions{}, fmt.Errorf("%w: %s: %s", ErrInvalidVolume, volume.Path, err)
+		}
+		fsType = detected
+	} else if !linux.SupportedFilesystem(fsType) {
+		return linux.DeviceOptions{}, fmt.Errorf("%w: unsupported filesystem %s", ErrInvalidVolume, fsType)
+	}
+
+	for _, option := range volume.MountOptions {
+		if len(option) == 0 || strings.Contains(option, ",") {
+			return linux.DeviceOptions{}, fmt.Errorf("%w: invalid mount option %q", ErrInvalidVolume, option)
+		}
+		if option == "ro" {
+			readOnly = true
+		}
+	}
+
+	return linux.DeviceOptions{
+		FsType:   fsType,
+		ReadOnly: readOnly || volume.ReadOnly || linux.ReadOnlyFilesystem(fsType),
+		Data:     strings.Join(volume.MountOptions, ","),
+	}, nil
+}
+
+// volumeMount is an additional volume image attached to the host.
+type volumeMount struct {
+	journal  *Journal
+	loopPath string
+	mount    linux.Mount
+	readOnly bool
+}
+
+func (v *volumeMount) release() error {
+	if err := v.journal.unmount(v.mount); err != nil {
+		return fmt.Errorf("v.journal.unmount: %w", err)
+	}
+	if err := v.journal.detachLoop(v.loopPath); err != nil {
+		return fmt.Errorf("v.journal.detachLoop: %w", err)
+	}
+	return nil
+}
+
+func attachVolume(journal *Journal, run string, volume *core.Volume, readOnly bool) (*volumeMount, error) {
+	device, err := volumeDevice(volume, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return mountVolume(journal, run, volume.Path, device)
+}
+
+// mountVolume attaches the image file to a loop device and mounts it, both are recorded in the journal.
+func mountVolume(journal *Journal, run string, path string, device linux.DeviceOptions) (*volumeMount, error) {
+	loopPath, err := journal.attachLoop(run, path, device.ReadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("journal.attachLoop: %w", err)
+	}
+
+	mount, err := linux.MountDevice(loopPath, device)
+	if err != nil {
+		_ = journal.detachLoop(loopPath)
+		return nil, fmt.Errorf("linux.MountDevice: %w", err)
+	}
+	if err := journal.recordMount(run, mount); err != nil {
+		_ = journal.detachLoop(loopPath)
+		return nil, fmt.Errorf("journal.recordMount: %w", err)
+	}
+
+	return &volumeMount{journal: journal, loopPath: loopPath, mount: mount, readOnly: device.ReadOnly}, nil
+}
+
+// containerMounts attaches the volumes and returns the specs applied by the entrypoint, parents first.
+// The returned release function detaches the volumes, it has to be called after the application exits.
+func containerMounts(ctx context.Context, journal *Journal, run string, mounts []*core.Mount) ([]linux.MountSpec, func(), error) {
+	if err := validateMounts(mounts); err != nil {
+		return nil, nil, err
+	}
+
+	var volumes []*volumeMount
+	release := func() {
+		for i := len(volumes) - 1; i >= 0; i-- {
+			if err := volumes[i].release(); err != nil {
+				log.With(ctx, "mount", volumes[i].mount.Path, "msg", err).Warn("could not release volume")
+			}
+		}
+	}
+
+	specs := make([]linux.MountSpec, 0, len(mounts))
+	for _, mount := range mounts {
+		spec := linux.MountSpec{
+			Destination: filepath.Clean(mount.Destination),
+			ReadOnly:    mount.ReadOnly,
+		}
+
+		switch source := mount.Source.(type) {
+		case *core.Mount_Bind:
+			spec.Kind = linux.MountBind
+			spec.Source = source.Bind.Source
+			spec.Recursive = source.Bind.Recursive
+		case *core.Mount_Tmpfs:
+			spec.Kind = linux.MountTmpfs
+			spec.Size = source.Tmpfs.Size
+			spec.Mode = source.Tmpfs.Mode
+		case *core.Mount_Volume:
+			volume, err := attachVolume(journal, run, source.Volume, mount.ReadOnly)
+			if err != nil {
+				release()
+				return nil, nil, fmt.Errorf("attachVolume: %w", err)
+			}
+			volumes = append(volumes, volume)
+
+			spec.Kind = linux.MountBind
+			spec.Source = volume.mount.Path
+			spec.ReadOnly = spec.ReadOnly || volume.readOnly
+		}
+
+		specs = append(specs, spec)
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return mountDepth(specs[i].Destination) < mountDepth(specs[j].Destination)
+	})
+
+	return specs, release, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerMounts(t *testing.T) {
+	source := t.TempDir()
+	specs, release, err := containerMounts(context.Background(), nil, "test", []*core.Mount{
+		{Destination: "/data/cache", Source: &core.Mount_Tmpfs{Tmpfs: &core.TmpfsMount{Size: 1 << 20}}},
+		{Destination: "/data/", ReadOnly: true, Source: &core.Mount_Bind{Bind: &core.BindMount{Source: source, Recursive: true}}},
+	})
+	require.NoError(t, err)
+	defer release()
+
+	require.Equal(t, []linux.MountSpec{
+		{Kind: linux.MountBind, Source: source, Destination: "/data", ReadOnly: true, Recursive: true},
+		{Kind: linux.MountTmpfs, Destination: "/data/cache", Size: 1 << 20},
+	}, specs)
+}
+
+func TestContainerMounts_Invalid(t *testing.T) {
+	invalid := [][]*core.Mount{
+		{{Destination: "data", Source: &core.Mount_Tmpfs{Tmpfs: &core.TmpfsMount{}}}},
+		{{Destination: "/data/../../etc", Source: &core.Mount_Tmpfs{Tmpfs: &core.TmpfsMount{}}}},
+		{{Destination: "/", Source: &core.Mount_Tmpfs{Tmpfs: &core.TmpfsMount{}}}},
+		{{Destination: "/data", Source: &core.Mount_Bind{Bind: &core.BindMount{Source: "../etc"}}}},
+		{{Destination: "/data"}},
+	}
+
+	for _, mounts := range invalid {
+		_, _, err := containerMounts(context.Background(), nil, "test", mounts)
+		require.True(t, errors.Is(err, ErrInvalidMount), "%v", mounts)
+	}
+}
+
+func TestVolumeDevice(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "image")
+	require.NoError(t, os.WriteFile(image, append([]byte("hsqs"), make([]byte, 4096)...), 0644))
+
+	// The squashfs images are detected and mounted read-only.
+	device, err := volumeDevice(&core.Volume{Path: image, MountOptions: []string{"noatime", "nodiratime"}}, false)
+	require.NoError(t, err)
+	require.Equal(t, linux.DeviceOptions{FsType: linux.FilesystemSquashfs, ReadOnly: true, Data: "noatime,nodiratime"}, device)
+
+	device, err = volumeDevice(&core.Volume{Path: image, FsType: linux.FilesystemXfs}, false)
+	require.NoError(t, err)
+	require.False(t, device.ReadOnly)
+
+	invalid := []*core.Volume{
+		{Path: image, FsType: "vfat"},
+		{Path: image, MountOptions: []string{"noatime,ro"}},
+		{Path: filepath.Join(t.TempDir(), "missing")},
+	}
+	for _, volume := range invalid {
+		_, err := volumeDevice(volume, false)
+		require.True(t, errors.Is(err, ErrInvalidVolume), "%v", volume)
+	}
+}
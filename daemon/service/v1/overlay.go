@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const OverlayRoot = "/opt/frama/overlay"
+
+// overlayLayer holds the writable part of a copy-on-write root.
+type overlayLayer struct {
+	Path  string
+	Upper string
+	Work  string
+}
+
+func newOverlayLayer(name string) (overlayLayer, error) {
+	path := filepath.Join(OverlayRoot, name)
+	layer := overlayLayer{
+		Path:  path,
+		Upper: filepath.Join(path, "upper"),
+		Work:  filepath.Join(path, "work"),
+	}
+
+	if err := os.MkdirAll(layer.Upper, 0755); err != nil {
+		return overlayLayer{}, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	if err := os.MkdirAll(layer.Work, 0755); err != nil {
+		return overlayLayer{}, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	return layer, nil
+}
+
+// Release removes the work directory, the upper directory is removed unless it should be kept.
+func (l overlayLayer) Release(keepUpper bool) error {
+	if !keepUpper {
+		return os.RemoveAll(l.Path)
+	}
+	return os.RemoveAll(l.Work)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+// defaultMaskedPaths expose the information about the host or allow to interfere with it.
+var defaultMaskedPaths = []string{
+	"/proc/acpi",
+	"/proc/asound",
+	"/proc/kcore",
+	"/proc/keys",
+	"/proc/latency_stats",
+	"/proc/sched_debug",
+	"/proc/scsi",
+	"/proc/timer_list",
+	"/proc/timer_stats",
+	"/sys/devices/virtual/powercap",
+	"/sys/firmware",
+}
+
+// defaultReadonlyPaths are the kernel settings, which are not namespaced.
+var defaultReadonlyPaths = []string{
+	"/proc/bus",
+	"/proc/fs",
+	"/proc/irq",
+	"/proc/sys",
+	"/proc/sysrq-trigger",
+}
+
+func validatePathRestrictions(restrictions *core.PathRestrictions) error {
+	if restrictions == nil {
+		return nil
+	}
+	for _, paths := range [][]string{restrictions.MaskedPaths, restrictions.ReadonlyPaths} {
+		for _, path := range paths {
+			if err := linux.ValidateMountPath(path); err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+			}
+			if filepath.Clean(path) == "/" {
+				return fmt.Errorf("%w: cannot restrict the root", ErrInvalidConfig)
+			}
+		}
+	}
+	return nil
+}
+
+// pathRestrictions returns the masked and the read-only paths, the defaults are used unless the application sets its own.
+func pathRestrictions(restrictions *core.PathRestrictions) ([]string, []string) {
+	if restrictions == nil {
+		return defaultMaskedPaths, defaultReadonlyPaths
+	}
+	return restrictions.MaskedPaths, restrictions.ReadonlyPaths
+}
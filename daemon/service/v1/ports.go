@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/network"
+)
+
+func portMappings(ports []*core.PortMapping) ([]network.PortMapping, error) {
+	result := make([]network.PortMapping, 0, len(ports))
+	for _, port := range ports {
+		if port.HostPort > math.MaxUint16 || port.ContainerPort > math.MaxUint16 {
+			return nil, fmt.Errorf("%w: port out of range", network.ErrInvalidPortMapping)
+		}
+
+		mapping := network.PortMapping{
+			Protocol:      network.ProtocolTCP,
+			HostPort:      uint16(port.HostPort),
+			ContainerPort: uint16(port.ContainerPort),
+		}
+		if port.Protocol == core.Protocol_PROTOCOL_UDP {
+			mapping.Protocol = network.ProtocolUDP
+		}
+		if len(port.HostIp) != 0 {
+			mapping.HostIP = net.ParseIP(port.HostIp)
+			if mapping.HostIP == nil {
+				return nil, fmt.Errorf("%w: invalid host ip %s", network.ErrInvalidPortMapping, port.HostIp)
+			}
+		}
+		result = append(result, mapping)
+	}
+
+	if err := network.ValidatePortMappings(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
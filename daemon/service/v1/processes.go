@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+var ErrInvalidProcess = errors.New("invalid init process")
+
+// processSpecs converts the processes supervised by the init of the container, their names have to be unique.
+func processSpecs(processes []*core.InitProcess) ([]process.Spec, error) {
+	names := map[string]bool{}
+	specs := make([]process.Spec, 0, len(processes))
+	for _, p := range processes {
+		if !namePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("%w: invalid name %q", ErrInvalidProcess, p.Name)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("%w: duplicated name %s", ErrInvalidProcess, p.Name)
+		}
+		names[p.Name] = true
+
+		if len(p.Arguments) == 0 || !filepath.IsAbs(p.Arguments[0]) {
+			return nil, fmt.Errorf("%w: %s requires an absolute path", ErrInvalidProcess, p.Name)
+		}
+		specs = append(specs, process.Spec{
+			Name:      p.Name,
+			Arguments: p.Arguments,
+			Restart:   p.Restart,
+		})
+	}
+	return specs, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/process"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessSpecs(t *testing.T) {
+	specs, err := processSpecs([]*core.InitProcess{
+		{Name: "metrics", Arguments: []string{"/bin/exporter", "--port", "9100"}, Restart: true},
+		{Name: "setup", Arguments: []string{"/bin/setup"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []process.Spec{
+		{Name: "metrics", Arguments: []string{"/bin/exporter", "--port", "9100"}, Restart: true},
+		{Name: "setup", Arguments: []string{"/bin/setup"}},
+	}, specs)
+}
+
+func TestProcessSpecs_Invalid(t *testing.T) {
+	invalid := [][]*core.InitProcess{
+		{{Name: "Invalid", Arguments: []string{"/bin/true"}}},
+		{{Name: "relative", Arguments: []string{"bin/true"}}},
+		{{Name: "empty"}},
+		{{Name: "twice", Arguments: []string{"/bin/true"}}, {Name: "twice", Arguments: []string{"/bin/false"}}},
+	}
+
+	for _, processes := range invalid {
+		_, err := processSpecs(processes)
+		require.True(t, errors.Is(err, ErrInvalidProcess), "%v", processes)
+	}
+}
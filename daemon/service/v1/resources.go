@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/cgroup"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+func parseDevice(device string) (uint32, uint32, error) {
+	if strings.HasPrefix(device, "/") {
+		var stat syscall.Stat_t
+		if err := syscall.Stat(device, &stat); err != nil {
+			return 0, 0, err
+		}
+		if stat.Mode&syscall.S_IFMT != syscall.S_IFBLK {
+			return 0, 0, fmt.Errorf("%s is not a block device", device)
+		}
+		return linux.Major(stat.Rdev), linux.Minor(stat.Rdev), nil
+	}
+
+	parts := strings.Split(device, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid device: %s", device)
+	}
+	major, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid device major: %s", device)
+	}
+	minor, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid device minor: %s", device)
+	}
+	return uint32(major), uint32(minor), nil
+}
+
+func resourceLimits(resources *core.Resources) (cgroup.Limits, error) {
+	if resources == nil {
+		return cgroup.Limits{}, nil
+	}
+
+	limits := cgroup.Limits{
+		MemoryMax:     resources.MemoryMax,
+		MemorySwapMax: resources.MemorySwapMax,
+		CPUQuota:      resources.CpuQuota,
+		CPUPeriod:     resources.CpuPeriod,
+		CPUWeight:     resources.CpuWeight,
+		PidsMax:       resources.PidsMax,
+	}
+
+	if limits.CPUWeight > 10000 {
+		return cgroup.Limits{}, fmt.Errorf("cpu weight out of range: %d", limits.CPUWeight)
+	}
+
+	for _, io := range resources.IoMax {
+		major, minor, err := parseDevice(io.Device)
+		if err != nil {
+			return cgroup.Limits{}, fmt.Errorf("parseDevice: %w", err)
+		}
+		limits.IO = append(limits.IO, cgroup.IOLimit{
+			Major:     major,
+			Minor:     minor,
+			ReadBps:   io.ReadBps,
+			WriteBps:  io.WriteBps,
+			ReadIOps:  io.ReadIops,
+			WriteIOps: io.WriteIops,
+		})
+	}
+
+	return limits, nil
+}
+
+func createCGroup(name string, resources *core.Resources) (cgroup.CGroup, error) {
+	limits, err := resourceLimits(resources)
+	if err != nil {
+		return cgroup.CGroup{}, fmt.Errorf("resourceLimits: %w", err)
+	}
+
+	group, err := cgroup.New(name, limits.Controllers())
+	if err != nil {
+		return cgroup.CGroup{}, fmt.Errorf("cgroup.New: %w", err)
+	}
+
+	if err := group.SetLimits(limits); err != nil {
+		_ = group.Remove()
+		return cgroup.CGroup{}, fmt.Errorf("group.SetLimits: %w", err)
+	}
+
+	return group, nil
+}
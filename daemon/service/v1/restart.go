@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+
+	// restartResetAfter resets the restart count of an application, which ran at least that long.
+	restartResetAfter = 10 * time.Minute
+)
+
+var ErrInvalidRestart = errors.New("invalid restart policy")
+
+func validateRestart(restart *core.Restart) error {
+	if restart == nil {
+		return nil
+	}
+	switch restart.Policy {
+	case core.RestartPolicy_RESTART_POLICY_NEVER,
+		core.RestartPolicy_RESTART_POLICY_ON_FAILURE,
+		core.RestartPolicy_RESTART_POLICY_ALWAYS:
+	default:
+		return fmt.Errorf("%w: unknown policy %d", ErrInvalidRestart, restart.Policy)
+	}
+	if restart.InitialBackoff != nil && restart.InitialBackoff.AsDuration() <= 0 {
+		return fmt.Errorf("%w: initial backoff must be positive", ErrInvalidRestart)
+	}
+	if restart.MaxBackoff != nil && restart.MaxBackoff.AsDuration() <= 0 {
+		return fmt.Errorf("%w: max backoff must be positive", ErrInvalidRestart)
+	}
+	return nil
+}
+
+func shouldRestart(restart *core.Restart, exitCode int32) bool {
+	switch restart.GetPolicy() {
+	case core.RestartPolicy_RESTART_POLICY_ALWAYS:
+		return true
+	case core.RestartPolicy_RESTART_POLICY_ON_FAILURE:
+		return exitCode != 0
+	}
+	return false
+}
+
+func retriesExhausted(restart *core.Restart, restarts uint32) bool {
+	return restart.GetMaxRetries() != 0 && restarts >= restart.GetMaxRetries()
+}
+
+// restartBackoff doubles the initial backoff with each consecutive restart.
+func restartBackoff(restart *core.Restart, restarts uint32) time.Duration {
+	backoff := defaultInitialBackoff
+	if restart.GetInitialBackoff() != nil {
+		backoff = restart.InitialBackoff.AsDuration()
+	}
+	maxBackoff := defaultMaxBackoff
+	if restart.GetMaxBackoff() != nil {
+		maxBackoff = restart.MaxBackoff.AsDuration()
+	}
+
+	for i := uint32(0); i < restarts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// supervise waits for the attempts of the run and restarts them according to the restart policy.
+// It releases the name of the run once the application is not going to be restarted.
+func (s *Service) supervise(ctx context.Context, run *Run, current *attempt) {
+	defer func() {
+		run.closeLog(ctx)
+		s.release(run.Name)
+		close(run.done)
+	}()
+
+	restart := run.options.Restart
+	for current != nil {
+		run.err = nil
+		if err := current.cmd.Wait(); err != nil {
+			run.err = fmt.Errorf("cmd.Wait: %w", err)
+		}
+		if current.terminal != nil {
+			code, _ := exitStatus(current.cmd.ProcessState)
+			current.terminal.finish(code)
+		}
+		current.cleanup.run()
+
+		run.exited(current.cmd.ProcessState)
+		s.recordProcess(ctx, run)
+		log.With(ctx, "process", run.Name, "status", current.cmd.ProcessState.String()).Info("application exited")
+
+		restarts := run.Process().RestartCount
+		if time.Since(current.started) >= restartResetAfter {
+			restarts = 0
+		}
+		current = s.restart(ctx, run, restart, restarts)
+	}
+}
+
+// restart delays and starts the next attempt, it returns nil if the run is not restarted.
+// A failed start counts as a failed attempt.
+func (s *Service) restart(ctx context.Context, run *Run, restart *core.Restart, restarts uint32) *attempt {
+	for {
+		if run.isStopping() || !shouldRestart(restart, run.Process().ExitCode) {
+			return nil
+		}
+		if retriesExhausted(restart, restarts) {
+			log.With(ctx, "process", run.Name, "restarts", restarts).Warn("restart limit reached, giving up")
+			return nil
+		}
+
+		backoff := restartBackoff(restart, restarts)
+		restarts++
+		run.update(func(process *core.Process) {
+			process.State = core.ProcessState_PROCESS_STATE_CRASH_LOOP_BACKOFF
+			process.RestartCount = restarts
+			process.NextRestartTime = timestamppb.New(time.Now().Add(backoff))
+		})
+		s.recordProcess(ctx, run)
+		log.With(ctx, "process", run.Name, "backoff", backoff.String(), "restarts", restarts).Info("restarting application")
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-run.stopping:
+			timer.Stop()
+			run.update(func(process *core.Process) {
+				process.State = core.ProcessState_PROCESS_STATE_EXITED
+				process.NextRestartTime = nil
+			})
+			s.recordProcess(ctx, run)
+			return nil
+		case <-timer.C:
+		}
+
+		current, err := s.startAttempt(ctx, run)
+		if err != nil {
+			log.With(ctx, "process", run.Name, "msg", err).Warn("could not restart application")
+			continue
+		}
+
+		// The run could have been stopped while it was starting, the stop waits for this attempt.
+		if run.isStopping() {
+			_ = current.cmd.Process.Kill()
+		}
+		return current
+	}
+}
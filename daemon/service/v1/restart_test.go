@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestShouldRestart(t *testing.T) {
+	require.False(t, shouldRestart(nil, 1))
+	require.False(t, shouldRestart(&core.Restart{Policy: core.RestartPolicy_RESTART_POLICY_ON_FAILURE}, 0))
+	require.True(t, shouldRestart(&core.Restart{Policy: core.RestartPolicy_RESTART_POLICY_ON_FAILURE}, 137))
+	require.True(t, shouldRestart(&core.Restart{Policy: core.RestartPolicy_RESTART_POLICY_ALWAYS}, 0))
+
+	restart := &core.Restart{Policy: core.RestartPolicy_RESTART_POLICY_ALWAYS, MaxRetries: 3}
+	require.False(t, retriesExhausted(restart, 2))
+	require.True(t, retriesExhausted(restart, 3))
+	require.False(t, retriesExhausted(&core.Restart{}, 100))
+}
+
+func TestRestartBackoff(t *testing.T) {
+	require.Equal(t, time.Second, restartBackoff(nil, 0))
+	require.Equal(t, 8*time.Second, restartBackoff(nil, 3))
+	require.Equal(t, defaultMaxBackoff, restartBackoff(nil, 1000))
+
+	restart := &core.Restart{
+		InitialBackoff: durationpb.New(100 * time.Millisecond),
+		MaxBackoff:     durationpb.New(time.Second),
+	}
+	require.Equal(t, 400*time.Millisecond, restartBackoff(restart, 2))
+	require.Equal(t, time.Second, restartBackoff(restart, 4))
+}
+
+func TestValidateRestart(t *testing.T) {
+	require.NoError(t, validateRestart(nil))
+	require.True(t, errors.Is(validateRestart(&core.Restart{Policy: 7}), ErrInvalidRestart))
+	require.True(t, errors.Is(validateRestart(&core.Restart{InitialBackoff: durationpb.New(0)}), ErrInvalidRestart))
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/cgroup"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrInvalidName = errors.New("invalid process name")
+	ErrRunExists   = errors.New("process with this name is already running")
+	ErrRunNotFound = errors.New("process is not running")
+)
+
+// The name is used in interface and cgroup names, so it is kept short and simple.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// cleanupStack collects the teardown of a run, it is executed in the reverse order.
+type cleanupStack []func()
+
+func (c *cleanupStack) push(fn func()) {
+	*c = append(*c, fn)
+}
+
+func (c *cleanupStack) run() {
+	for i := len(*c) - 1; i >= 0; i-- {
+		(*c)[i]()
+	}
+	*c = nil
+}
+
+// attempt is a single execution of the entrypoint, a run consists of several attempts if it is restarted.
+type attempt struct {
+	cmd      *exec.Cmd
+	group    cgroup.CGroup
+	terminal *runTerminal
+	cleanup  cleanupStack
+	started  time.Time
+}
+
+// Run is an application started by the service.
+type Run struct {
+	Name string
+
+	options     *core.RunOptions
+	volume      *core.Volume
+	application *core.Application
+	stdio       Stdio
+	log         *runLog
+
+	done     chan struct{}
+	stopping chan struct{}
+	stopOnce sync.Once
+	err      error
+
+	mu      sync.Mutex
+	current *attempt
+	process *core.Process
+}
+
+func newRun(name string, volume *core.Volume, application *core.Application, options *core.RunOptions, stdio Stdio) *Run {
+	return &Run{
+		Name:        name,
+		options:     options,
+		volume:      volume,
+		application: application,
+		stdio:       stdio,
+		done:        make(chan struct{}),
+		stopping:    make(chan struct{}),
+		process: &core.Process{
+			Name:        name,
+			State:       core.ProcessState_PROCESS_STATE_CREATED,
+			Application: application,
+			Volume:      volume,
+		},
+	}
+}
+
+// Process returns a copy of the current state of the run.
+func (r *Run) Process() *core.Process {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return proto.Clone(r.process).(*core.Process)
+}
+
+func (r *Run) update(fn func(process *core.Process)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.process)
+}
+
+// attempt returns the running attempt, it is nil between the restarts.
+func (r *Run) attempt() *attempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Pid returns the pid of the running entrypoint or 0 if it is not running.
+func (r *Run) Pid() int {
+	current := r.attempt()
+	if current == nil {
+		return 0
+	}
+	return current.cmd.Process.Pid
+}
+
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the application exits for the last time and its resources are released.
+func (r *Run) Wait() error {
+	<-r.done
+	return r.err
+}
+
+// markStopping prevents further restarts.
+func (r *Run) markStopping() {
+	r.stopOnce.Do(func() {
+		close(r.stopping)
+	})
+}
+
+func (r *Run) isStopping() bool {
+	select {
+	case <-r.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Run) started(current *attempt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = current
+	r.process.State = core.ProcessState_PROCESS_STATE_RUNNING
+	r.process.Pid = int64(current.cmd.Process.Pid)
+	r.process.StartTime = timestamppb.New(current.started)
+	r.process.ExitTime = nil
+	r.process.ExitCode = 0
+	r.process.Signal = ""
+	r.process.NextRestartTime = nil
+	r.process.SetupError = ""
+}
+
+// exitStatus returns the exit code and the name of the signal, which killed the process.
+// A process killed by a signal gets 128+signal as the exit code, like in shells.
+func exitStatus(state *os.ProcessState) (int32, string) {
+	if state == nil {
+		return -1, ""
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok {
+		return int32(state.ExitCode()), ""
+	}
+	if status.Signaled() {
+		return 128 + int32(status.Signal()), linux.SignalName(status.Signal())
+	}
+	return int32(status.ExitStatus()), ""
+}
+
+func (r *Run) exited(state *os.ProcessState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = nil
+	r.process.State = core.ProcessState_PROCESS_STATE_EXITED
+	r.process.ExitTime = timestamppb.Now()
+	r.process.ExitCode, r.process.Signal = exitStatus(state)
+}
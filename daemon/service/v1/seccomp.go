@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/seccomp"
+)
+
+func seccompAction(action core.SeccompAction) (seccomp.Action, error) {
+	switch action {
+	case core.SeccompAction_SECCOMP_ACTION_ALLOW:
+		return seccomp.ActionAllow, nil
+	case core.SeccompAction_SECCOMP_ACTION_ERRNO:
+		return seccomp.ActionErrno, nil
+	case core.SeccompAction_SECCOMP_ACTION_KILL_PROCESS:
+		return seccomp.ActionKillProcess, nil
+	case core.SeccompAction_SECCOMP_ACTION_KILL_THREAD:
+		return seccomp.ActionKillThread, nil
+	case core.SeccompAction_SECCOMP_ACTION_TRAP:
+		return seccomp.ActionTrap, nil
+	case core.SeccompAction_SECCOMP_ACTION_LOG:
+		return seccomp.ActionLog, nil
+	}
+	return 0, fmt.Errorf("unknown seccomp action: %d", action)
+}
+
+func seccompOperator(op core.SeccompOperator) (seccomp.Operator, error) {
+	switch op {
+	case core.SeccompOperator_SECCOMP_OPERATOR_EQUAL:
+		return seccomp.OpEqual, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_NOT_EQUAL:
+		return seccomp.OpNotEqual, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_LESS:
+		return seccomp.OpLess, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_LESS_EQUAL:
+		return seccomp.OpLessEqual, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_GREATER:
+		return seccomp.OpGreater, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_GREATER_EQUAL:
+		return seccomp.OpGreaterEqual, nil
+	case core.SeccompOperator_SECCOMP_OPERATOR_MASKED_EQUAL:
+		return seccomp.OpMaskedEqual, nil
+	}
+	return 0, fmt.Errorf("unknown seccomp operator: %d", op)
+}
+
+func seccompProfile(profile *core.SeccompProfile) (seccomp.Profile, error) {
+	if profile == nil {
+		return seccomp.DefaultProfile, nil
+	}
+
+	defaultAction, err := seccompAction(profile.DefaultAction)
+	if err != nil {
+		return seccomp.Profile{}, err
+	}
+
+	result := seccomp.Profile{
+		DefaultAction: defaultAction,
+		DefaultErrno:  uint16(profile.DefaultErrno),
+	}
+
+	for _, rule := range profile.Rules {
+		action, err := seccompAction(rule.Action)
+		if err != nil {
+			return seccomp.Profile{}, err
+		}
+
+		args := make([]seccomp.Arg, 0, len(rule.Args))
+		for _, arg := range rule.Args {
+			op, err := seccompOperator(arg.Op)
+			if err != nil {
+				return seccomp.Profile{}, err
+			}
+			args = append(args, seccomp.Arg{
+				Index:    uint(arg.Index),
+				Op:       op,
+				Value:    arg.Value,
+				ValueTwo: arg.ValueTwo,
+			})
+		}
+
+		result.Rules = append(result.Rules, seccomp.Rule{
+			Names:  rule.Names,
+			Action: action,
+			Errno:  uint16(rule.Errno),
+			Args:   args,
+		})
+	}
+
+	return result, nil
+}
+
+// seccompProgram compiles the profile, the entrypoint loads the program right before executing the application.
+func seccompProgram(profile *core.SeccompProfile) ([]byte, error) {
+	converted, err := seccompProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("seccompProfile: %w", err)
+	}
+
+	program, err := seccomp.Compile(converted)
+	if err != nil {
+		return nil, fmt.Errorf("seccomp.Compile: %w", err)
+	}
+	return program.Marshal(), nil
+}
@@ -3,77 +3,610 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
 	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
 	"github.com/mmbednarek/fragma/pkg/linux"
 	"github.com/mmbednarek/fragma/pkg/log"
+	"github.com/mmbednarek/fragma/pkg/network"
+	"github.com/mmbednarek/fragma/pkg/protoutil"
+	"github.com/mmbednarek/fragma/pkg/util"
 )
 
+const DefaultEntrypointPath = "/usr/local/bin/fragma-entrypoint"
+
+// ProcessRecorder persists the state of the runs, so they can be listed with fractl.
+type ProcessRecorder interface {
+	RecordProcess(process *core.Process) error
+}
+
+// Stdio of the application, nil readers and writers are connected to /dev/null.
+// With a terminal the output is written to Stdout and Stdin is not used, the input comes from the attachments.
+type Stdio struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
 type Service struct {
+	entrypointPath string
+	network        *network.Manager
+	recorder       ProcessRecorder
+	logDir         string
+	journal        *Journal
+	images         *imageManager
+	// imageIdleTimeout is how long the shared images stay mounted without any run using them.
+	imageIdleTimeout time.Duration
+	setupTimeout     time.Duration
+
+	// attaching is held while the starting runs attach their volumes and network, so the garbage collection does not see them half recorded.
+	attaching sync.RWMutex
+
+	mu      sync.Mutex
+	runs    map[string]*Run
+	stopped bool
+}
+
+func WithEntrypoint(path string) func(s *Service) {
+	return func(s *Service) {
+		s.entrypointPath = path
+	}
+}
+
+// WithNetwork connects the containers, which do not share the host network, to a bridge.
+// Without it the containers only have the loopback device.
+func WithNetwork(manager *network.Manager) func(s *Service) {
+	return func(s *Service) {
+		s.network = manager
+	}
+}
+
+func WithImageIdleTimeout(timeout time.Duration) func(s *Service) {
+	return func(s *Service) {
+		s.imageIdleTimeout = timeout
+	}
+}
+
+// WithSetupTimeout limits the setup of a container, the processes of a container, which takes longer, are killed.
+func WithSetupTimeout(timeout time.Duration) func(s *Service) {
+	return func(s *Service) {
+		s.setupTimeout = timeout
+	}
+}
+
+// WithJournal records the loop devices and the mounts of the runs, so they can be released after a crash.
+func WithJournal(journal *Journal) func(s *Service) {
+	return func(s *Service) {
+		s.journal = journal
+	}
+}
+
+func WithProcessRecorder(recorder ProcessRecorder) func(s *Service) {
+	return func(s *Service) {
+		s.recorder = recorder
+	}
 }
 
-func NewService() *Service {
-	return &Service{}
+func NewService(opts ...func(s *Service)) *Service {
+	s := &Service{
+		entrypointPath:   DefaultEntrypointPath,
+		imageIdleTimeout: DefaultImageIdleTimeout,
+		setupTimeout:     DefaultSetupTimeout,
+		runs:             map[string]*Run{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.images = newImageManager(s.journal, s.imageIdleTimeout, &s.attaching)
+
+	return s
+}
+
+func attachmentAddress(attachment *network.Attachment) string {
+	if attachment == nil {
+		return "none"
+	}
+	return attachment.Address.String()
+}
+
+// entrypointArgs builds the command line of the entrypoint, the container is described by the config it reads.
+func entrypointArgs() []string {
+	return []string{
+		"-config-fd", strconv.Itoa(configFd),
+		"-sync-fd", strconv.Itoa(syncFd),
+		"-status-fd", strconv.Itoa(statusFd),
+	}
+}
+
+func applicationEnv(environment map[string]string) []string {
+	env := []string{
+		"PS1=[fragma] # ",
+		"TERM=xterm",
+		"HOME=/root",
+	}
+	for key, value := range environment {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	return env
 }
 
+// setupNetwork configures the network namespace of the entrypoint, which waits for it on the sync descriptor.
+func (s *Service) setupNetwork(name string, pid int, files etcFiles, options *core.RunOptions) (*network.Attachment, error) {
+	if err := network.SetupLoopback(pid); err != nil {
+		return nil, fmt.Errorf("network.SetupLoopback: %w", err)
+	}
+
+	var attachment *network.Attachment
+	var ip net.IP
+	if s.network != nil {
+		// The address is allocated while attaching is held, so the garbage collection does not take it for a leak.
+		s.attaching.RLock()
+		var err error
+		attachment, err = s.network.Attach(name, pid)
+		s.attaching.RUnlock()
+		if err != nil {
+			return nil, fmt.Errorf("s.network.Attach: %w", err)
+		}
+		ip = attachment.Address.IP
+	}
+
+	nameservers := options.DnsServers
+	if len(nameservers) == 0 {
+		nameservers = network.HostNameservers()
+	}
+
+	if err := network.WriteResolvConf(files.ResolvConf, nameservers, options.DnsSearch); err != nil {
+		return attachment, fmt.Errorf("network.WriteResolvConf: %w", err)
+	}
+
+	if err := network.WriteHosts(files.Hosts, hostname(options), ip); err != nil {
+		return attachment, fmt.Errorf("network.WriteHosts: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// recordProcess only warns on failure, the application runs even if it cannot be listed.
+func (s *Service) recordProcess(ctx context.Context, run *Run) {
+	if s.recorder == nil {
+		return
+	}
+	if err := s.recorder.RecordProcess(run.Process()); err != nil {
+		log.With(ctx, "process", run.Name, "msg", err).Warn("could not record process")
+	}
+}
+
+func (s *Service) reserve(run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrServiceStopped
+	}
+	if _, ok := s.runs[run.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrRunExists, run.Name)
+	}
+	s.runs[run.Name] = run
+	return nil
+}
+
+func (s *Service) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.runs, name)
+}
+
+// FindRun returns a run, which has not exited yet.
+func (s *Service) FindRun(name string) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, name)
+	}
+	return run, nil
+}
+
+// RunApplication runs the application attached to the stdio of the daemon and waits until it exits,
+// the signals received by the daemon in the meantime are forwarded to the application.
 func (s *Service) RunApplication(ctx context.Context, volume *core.Volume, application *core.Application, options *core.RunOptions) error {
-	loopPath, err := linux.LoopSetupDevice(volume.Path)
+	run, err := s.StartApplication(ctx, "", volume, application, options, Stdio{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
 	if err != nil {
 		return err
 	}
-	defer linux.LoopClear(loopPath)
 
-	mount, err := linux.MountDevice(loopPath)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case sig := <-signals:
+			if err := run.Signal(sig.(syscall.Signal)); err != nil {
+				log.With(ctx, "signal", sig.String(), "msg", err).Warn("could not forward signal")
+			}
+		case <-run.Done():
+			return run.Wait()
+		}
+	}
+}
+
+// StartApplication starts the application in the background, the resources of the run are released
+// as soon as it exits and it is restarted according to its restart policy. If name is empty, a random one is generated.
+func (s *Service) StartApplication(ctx context.Context, name string, volume *core.Volume, application *core.Application, options *core.RunOptions, stdio Stdio) (*Run, error) {
+	if len(name) == 0 {
+		name = util.String(8)
+	}
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+	if err := validateRestart(options.Restart); err != nil {
+		return nil, fmt.Errorf("validateRestart: %w", err)
+	}
+	if _, err := processSpecs(options.Processes); err != nil {
+		return nil, fmt.Errorf("processSpecs: %w", err)
+	}
+	if err := validateConfig(options); err != nil {
+		return nil, fmt.Errorf("validateConfig: %w", err)
+	}
+
+	run := newRun(name, volume, application, options, stdio)
+	if err := s.reserve(run); err != nil {
+		return nil, err
+	}
+	if err := s.openLog(run); err != nil {
+		s.release(name)
+		return nil, fmt.Errorf("s.openLog: %w", err)
+	}
+
+	current, err := s.startAttempt(ctx, run)
 	if err != nil {
-		return err
+		// The run is already visible, so Stop and StopAll could be waiting for it to finish.
+		if run.Process().State == core.ProcessState_PROCESS_STATE_CREATED {
+			s.failed(ctx, run, nil)
+		}
+		run.err = err
+		run.closeLog(ctx)
+		s.release(name)
+		close(run.done)
+		return nil, err
 	}
-	defer mount.Unmount()
 
-	cmd := exec.Command(application.Path)
+	// The run could have been stopped while it was starting, the stop waits for this attempt.
+	if run.isStopping() {
+		_ = current.cmd.Process.Kill()
+	}
+	go s.supervise(ctx, run, current)
+	return run, nil
+}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stdin = os.Stdin
-	cmd.Stderr = os.Stderr
+// mountOverlay mounts a writable overlay of the lower directory, it is released by the cleanup of the run.
+func (s *Service) mountOverlay(ctx context.Context, name string, lower string, options *core.RunOptions, cleanup *cleanupStack) (string, error) {
+	layer, err := newOverlayLayer(util.String(6))
+	if err != nil {
+		return "", fmt.Errorf("newOverlayLayer: %w", err)
+	}
+	cleanup.push(func() {
+		if err := layer.Release(options.KeepOverlayUpper); err != nil {
+			log.With(ctx, "overlay", layer.Path, "msg", err).Warn("could not release overlay layer")
+		}
+		if options.KeepOverlayUpper {
+			log.With(ctx, "upper", layer.Upper).Info("kept overlay upper directory")
+		}
+	})
 
-	cmd.Env = []string{
-		"PS1=[fragma] # ",
-		"TERM=xterm",
-		"HOME=/root",
+	overlay, err := linux.MountOverlay(lower, layer.Upper, layer.Work)
+	if err != nil {
+		return "", fmt.Errorf("linux.MountOverlay: %w", err)
+	}
+	if err := s.journal.recordMount(name, overlay); err != nil {
+		return "", fmt.Errorf("s.journal.recordMount: %w", err)
+	}
+	cleanup.push(func() {
+		if err := s.journal.unmount(overlay); err != nil {
+			log.With(ctx, "mount", overlay.Path, "msg", err).Warn("could not unmount overlay")
+		}
+	})
+	return overlay.Path, nil
+}
+
+// attachVolumes mounts the root and the volumes of the run. It holds attaching, so the garbage collection
+// does not see the devices before they are recorded in the journal, the rest of the start does not need it.
+func (s *Service) attachVolumes(ctx context.Context, run *Run, device linux.DeviceOptions, cleanup *cleanupStack) (string, []linux.MountSpec, error) {
+	s.attaching.RLock()
+	defer s.attaching.RUnlock()
+
+	var rootfs string
+	if device.ReadOnly {
+		// The changes to a read-only image are written to the overlay of the run.
+		image, err := s.images.acquire(run.volume.Path, device)
+		if err != nil {
+			return "", nil, fmt.Errorf("s.images.acquire: %w", err)
+		}
+		cleanup.push(func() { s.images.release(image) })
+
+		rootfs, err = s.mountOverlay(ctx, run.Name, image.volume.mount.Path, run.options, cleanup)
+		if err != nil {
+			return "", nil, fmt.Errorf("s.mountOverlay: %w", err)
+		}
+	} else {
+		image, err := s.images.mountWritable(run.Name, run.volume.Path, device)
+		if err != nil {
+			return "", nil, fmt.Errorf("s.images.mountWritable: %w", err)
+		}
+		cleanup.push(func() {
+			if err := s.images.releaseWritable(image); err != nil {
+				log.With(ctx, "mount", image.volume.mount.Path, "msg", err).Warn("could not release volume")
+			}
+		})
+		rootfs = image.volume.mount.Path
+	}
+
+	specs, releaseVolumes, err := containerMounts(ctx, s.journal, run.Name, run.options.Mounts)
+	if err != nil {
+		return "", nil, fmt.Errorf("containerMounts: %w", err)
+	}
+	cleanup.push(releaseVolumes)
+	return rootfs, specs, nil
+}
+
+// startAttempt prepares the resources of the run and starts the entrypoint. If it fails, the resources
+// are released and the failure is recorded.
+func (s *Service) startAttempt(ctx context.Context, run *Run) (*attempt, error) {
+	name := run.Name
+	volume := run.volume
+	application := run.application
+	options := run.options
+	stdio := run.stdio
+
+	current := &attempt{}
+	cleanup := &current.cleanup
+	started := false
+	defer func() {
+		if !started {
+			cleanup.run()
+		}
+	}()
+
+	userNs, err := newUserNamespace(options)
+	if err != nil {
+		return nil, fmt.Errorf("newUserNamespace: %w", err)
+	}
+
+	caps, err := newCapabilitySets(options.Capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("newCapabilitySets: %w", err)
+	}
+
+	if _, err := stopSignal(options, ""); err != nil {
+		return nil, fmt.Errorf("stopSignal: %w", err)
+	}
+
+	if options.ShareHostNetwork && len(options.Ports) > 0 {
+		return nil, fmt.Errorf("%w: publishing ports requires a separate network namespace", network.ErrInvalidPortMapping)
+	}
+	ports, err := portMappings(options.Ports)
+	if err != nil {
+		return nil, fmt.Errorf("portMappings: %w", err)
+	}
+
+	program, err := seccompProgram(options.Seccomp)
+	if err != nil {
+		return nil, fmt.Errorf("seccompProgram: %w", err)
+	}
+
+	// In the overlay mode the image is never modified, so it can be shared between runs.
+	device, err := volumeDevice(volume, options.Overlay)
+	if err != nil {
+		return nil, fmt.Errorf("volumeDevice: %w", err)
+	}
+
+	rootfs, specs, err := s.attachVolumes(ctx, run, device, cleanup)
+	if err != nil {
+		return nil, fmt.Errorf("s.attachVolumes: %w", err)
+	}
+
+	// The network files of the container cover the ones of the image, the host network keeps the files of the image.
+	var files etcFiles
+	if !options.ShareHostNetwork {
+		files, err = newEtcFiles(util.String(6))
+		if err != nil {
+			return nil, fmt.Errorf("newEtcFiles: %w", err)
+		}
+		cleanup.push(func() {
+			if err := files.Release(); err != nil {
+				log.With(ctx, "path", files.Path, "msg", err).Warn("could not remove network files")
+			}
+		})
+		specs = append(files.mounts(), specs...)
 	}
-	for key, value := range options.Environment {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+
+	group, err := createCGroup(name, options.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("createCGroup: %w", err)
+	}
+	current.group = group
+	cleanup.push(func() {
+		if err := group.Remove(); err != nil {
+			log.With(ctx, "cgroup", group.Path, "msg", err).Warn("could not remove cgroup")
+		}
+	})
+
+	if err := group.AllowDevices(deviceRules(containerDevices(options.Devices))); err != nil {
+		return nil, fmt.Errorf("group.AllowDevices: %w", err)
+	}
+
+	groupFd, err := group.Open()
+	if err != nil {
+		return nil, fmt.Errorf("group.Open: %w", err)
 	}
+	cleanup.push(func() { syscall.Close(groupFd) })
+
+	config, err := protoutil.Pipe(containerConfig(rootfs, application, options, caps, specs, program))
+	if err != nil {
+		return nil, fmt.Errorf("protoutil.Pipe: %w", err)
+	}
+	defer config.Close()
+
+	syncReader, syncWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("os.Pipe: %w", err)
+	}
+	defer syncReader.Close()
+	cleanup.push(func() { syncWriter.Close() })
+
+	statusReader, statusWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("os.Pipe: %w", err)
+	}
+	defer statusReader.Close()
+	defer statusWriter.Close()
+
+	cmd := exec.Command(s.entrypointPath, entrypointArgs()...)
+
+	if options.Tty {
+		terminal, slave, err := newRunTerminal(stdio.Stdout)
+		if err != nil {
+			return nil, fmt.Errorf("newRunTerminal: %w", err)
+		}
+		defer slave.Close()
+		current.terminal = terminal
+		cleanup.push(func() { terminal.finish(-1) })
+
+		cmd.Stdin = slave
+		cmd.Stdout = slave
+		cmd.Stderr = slave
+	} else {
+		cmd.Stdin = stdio.Stdin
+		cmd.Stdout = stdio.Stdout
+		cmd.Stderr = stdio.Stderr
+	}
+	cmd.ExtraFiles = []*os.File{config, syncReader, statusWriter}
+	// The environment of the application is a part of the config, the entrypoint does not need one.
+	cmd.Env = []string{}
 
 	gid := syscall.Getgid()
 	uid := syscall.Getuid()
 
 	log.With(ctx, "gid", gid, "uid", uid).Info("running with")
 
-	cloneFlags := syscall.CLONE_NEWIPC | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUTS
+	cloneFlags := syscall.CLONE_NEWIPC | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUTS | linux.CLONE_NEWCGROUP
 	if !options.ShareHostNetwork {
 		cloneFlags |= syscall.CLONE_NEWNET
 	}
 
-	cmd.Dir = "/root"
-	cmd.Args = options.Arguments
 	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Chroot: mount.Path,
 		Credential: &syscall.Credential{
 			Uid: 0,
 			Gid: 0,
 		},
-		Cloneflags: uintptr(cloneFlags),
+		Cloneflags:  uintptr(cloneFlags),
+		UseCgroupFD: true,
+		CgroupFD:    groupFd,
 	}
+	if userNs != nil {
+		userNs.Apply(cmd.SysProcAttr)
+	}
+	s.recordProcess(ctx, run)
 
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("cmd.Run: %w", err)
+		s.failed(ctx, run, nil)
+		return nil, fmt.Errorf("cmd.Run: %w", err)
 	}
+	// Only the entrypoint keeps the write end, so the status is read until it starts the application or exits.
+	statusWriter.Close()
 
-	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("cmd.Wait: %w", err)
+	// From now on the entrypoint has to be reaped before the resources are released.
+	abort := func() {
+		syncWriter.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		s.failed(ctx, run, cmd.ProcessState)
 	}
-	return nil
+
+	if !options.ShareHostNetwork {
+		attachment, err := s.setupNetwork(name, cmd.Process.Pid, files, options)
+		if attachment != nil {
+			cleanup.push(func() {
+				if err := s.network.Detach(attachment); err != nil {
+					log.With(ctx, "interface", attachment.HostInterface, "msg", err).Warn("could not detach network")
+				}
+			})
+		}
+		if err != nil {
+			abort()
+			return nil, fmt.Errorf("s.setupNetwork: %w", err)
+		}
+		log.With(ctx, "hostname", hostname(options), "address", attachmentAddress(attachment)).Info("configured network")
+
+		if len(ports) > 0 {
+			proxy, err := network.StartProxy(cmd.Process.Pid, ports)
+			if err != nil {
+				abort()
+				return nil, fmt.Errorf("network.StartProxy: %w", err)
+			}
+			cleanup.push(proxy.Close)
+		}
+
+		run.update(func(process *core.Process) {
+			process.Address = attachmentAddress(attachment)
+			process.Ports = options.Ports
+		})
+	} else {
+		run.update(func(process *core.Process) {
+			process.Address = "host"
+		})
+	}
+
+	if _, err := syncWriter.Write([]byte{0}); err != nil {
+		abort()
+		return nil, fmt.Errorf("syncWriter.Write: %w", err)
+	}
+	syncWriter.Close()
+
+	setupErr, err := readSetupStatus(ctx, statusReader, s.setupTimeout)
+	if err != nil {
+		// The processes started by the entrypoint could keep its output open, so they are killed as well.
+		if err := group.Kill(); err != nil {
+			log.With(ctx, "cgroup", group.Path, "msg", err).Warn("could not kill cgroup")
+		}
+		abort()
+		return nil, fmt.Errorf("readSetupStatus: %w", err)
+	}
+	if len(setupErr) != 0 {
+		run.update(func(process *core.Process) {
+			process.SetupError = setupErr
+		})
+		abort()
+		return nil, fmt.Errorf("%w: %s", ErrContainerSetup, setupErr)
+	}
+
+	current.cmd = cmd
+	current.started = time.Now()
+	run.started(current)
+	s.recordProcess(ctx, run)
+	log.With(ctx, "process", name, "pid", cmd.Process.Pid).Info("started application")
+
+	started = true
+	return current, nil
+}
+
+// failed records a run, which could not be started.
+func (s *Service) failed(ctx context.Context, run *Run, state *os.ProcessState) {
+	run.exited(state)
+	s.recordProcess(ctx, run)
 }
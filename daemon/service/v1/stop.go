@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const DefaultStopSignal = syscall.SIGTERM
+
+const DefaultStopTimeout = 10 * time.Second
+
+var (
+	ErrInvalidSignal  = errors.New("invalid signal")
+	ErrServiceStopped = errors.New("service is stopped")
+)
+
+func parseSignal(name string) (syscall.Signal, error) {
+	sig, err := linux.SignalFromName(name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidSignal, err)
+	}
+	return sig, nil
+}
+
+// stopSignal returns the requested signal, the stop signal of the run or SIGTERM.
+func stopSignal(options *core.RunOptions, requested string) (syscall.Signal, error) {
+	switch {
+	case len(requested) != 0:
+		return parseSignal(requested)
+	case len(options.StopSignal) != 0:
+		return parseSignal(options.StopSignal)
+	}
+	return DefaultStopSignal, nil
+}
+
+func stopTimeout(options *core.RunOptions, requested *durationpb.Duration) time.Duration {
+	switch {
+	case requested != nil:
+		return requested.AsDuration()
+	case options.StopTimeout != nil:
+		return options.StopTimeout.AsDuration()
+	}
+	return DefaultStopTimeout
+}
+
+// signalAttempt sends the signal to the entrypoint, which forwards it to the application.
+// Between the restarts there is no process and it does nothing.
+func (r *Run) signalAttempt(sig syscall.Signal) error {
+	current := r.attempt()
+	if current == nil {
+		return nil
+	}
+	if err := current.cmd.Process.Signal(sig); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+		return fmt.Errorf("current.cmd.Process.Signal: %w", err)
+	}
+	return nil
+}
+
+func (r *Run) Signal(sig syscall.Signal) error {
+	if r.attempt() == nil {
+		return fmt.Errorf("%w: %s is waiting for a restart", ErrRunNotFound, r.Name)
+	}
+	return r.signalAttempt(sig)
+}
+
+// stop prevents further restarts, sends the stop signal and kills the pid namespace after the timeout.
+// It returns once the resources of the run are released.
+func (r *Run) stop(ctx context.Context, sig syscall.Signal, timeout time.Duration) error {
+	r.markStopping()
+	if err := r.signalAttempt(sig); err != nil {
+		return fmt.Errorf("r.signalAttempt: %w", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-timer.C:
+	}
+
+	log.With(ctx, "process", r.Name, "timeout", timeout.String()).Warn("process did not stop in time, killing")
+	// The rest of the pid namespace is killed together with its init.
+	if err := r.signalAttempt(syscall.SIGKILL); err != nil {
+		return fmt.Errorf("r.signalAttempt: %w", err)
+	}
+	<-r.done
+	return nil
+}
+
+func (s *Service) SignalRun(name string, signal string) error {
+	sig, err := parseSignal(signal)
+	if err != nil {
+		return err
+	}
+	run, err := s.FindRun(name)
+	if err != nil {
+		return err
+	}
+	return run.Signal(sig)
+}
+
+// Stop stops the run gracefully, empty signal and nil timeout fall back to the options of the run.
+func (s *Service) Stop(ctx context.Context, name string, signal string, timeout *durationpb.Duration) (*core.Process, error) {
+	run, err := s.FindRun(name)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := stopSignal(run.options, signal)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := run.stop(ctx, sig, stopTimeout(run.options, timeout)); err != nil {
+		return nil, fmt.Errorf("run.stop: %w", err)
+	}
+	return run.Process(), nil
+}
+
+// StopAll stops all runs and refuses to start new ones, it is called when the daemon exits.
+func (s *Service) StopAll(ctx context.Context) {
+	s.mu.Lock()
+	s.stopped = true
+	runs := make([]*Run, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, run)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		run := run
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sig, err := stopSignal(run.options, "")
+			if err != nil {
+				sig = DefaultStopSignal
+			}
+			if err := run.stop(ctx, sig, stopTimeout(run.options, nil)); err != nil {
+				log.With(ctx, "process", run.Name, "msg", err).Warn("could not stop process")
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.images.close(ctx)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestStopSignal(t *testing.T) {
+	sig, err := stopSignal(&core.RunOptions{}, "")
+	require.NoError(t, err)
+	require.Equal(t, syscall.SIGTERM, sig)
+
+	options := &core.RunOptions{StopSignal: "SIGINT"}
+	sig, err = stopSignal(options, "")
+	require.NoError(t, err)
+	require.Equal(t, syscall.SIGINT, sig)
+
+	sig, err = stopSignal(options, "quit")
+	require.NoError(t, err)
+	require.Equal(t, syscall.SIGQUIT, sig)
+
+	_, err = stopSignal(&core.RunOptions{StopSignal: "SIGNOPE"}, "")
+	require.True(t, errors.Is(err, ErrInvalidSignal))
+}
+
+func TestStopTimeout(t *testing.T) {
+	require.Equal(t, DefaultStopTimeout, stopTimeout(&core.RunOptions{}, nil))
+
+	options := &core.RunOptions{StopTimeout: durationpb.New(time.Minute)}
+	require.Equal(t, time.Minute, stopTimeout(options, nil))
+	require.Equal(t, time.Duration(0), stopTimeout(options, durationpb.New(0)))
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+var ErrNoTerminal = errors.New("process has no terminal")
+
+// replaySize is the amount of the recent output sent to a new attachment, so it shows the current prompt.
+const replaySize = 8 << 10
+
+// attachmentQueueSize is the number of the output chunks buffered for a client, a client falling further behind is disconnected.
+const attachmentQueueSize = 64
+
+// outputDrainTimeout limits waiting for the output of an exited attempt, before the master is closed.
+const outputDrainTimeout = time.Second
+
+// runTerminal is the terminal of an attempt, the daemon keeps its master side and the clients attach to it.
+type runTerminal struct {
+	terminal linux.Terminal
+	output   io.Writer
+	copied   <-chan struct{}
+	close    sync.Once
+
+	mu          sync.Mutex
+	replay      []byte
+	attachments map[*Attachment]struct{}
+	exitCode    int32
+	exited      bool
+}
+
+// newRunTerminal creates the terminal, the returned slave is passed to the entrypoint as its stdio.
+func newRunTerminal(output io.Writer) (*runTerminal, *os.File, error) {
+	terminal, err := linux.NewTerminal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("linux.NewTerminal: %w", err)
+	}
+	slave, err := terminal.OpenSlave()
+	if err != nil {
+		terminal.Close()
+		return nil, nil, fmt.Errorf("terminal.OpenSlave: %w", err)
+	}
+	if output == nil {
+		output = io.Discard
+	}
+
+	t := &runTerminal{
+		terminal:    terminal,
+		output:      output,
+		attachments: map[*Attachment]struct{}{},
+	}
+	t.copied = terminal.Pump(nil, t)
+	return t, slave, nil
+}
+
+// Write sends the output of the terminal to the log and the attachments.
+func (t *runTerminal) Write(data []byte) (int, error) {
+	_, _ = t.output.Write(data)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.replay = append(t.replay, data...)
+	if len(t.replay) > replaySize {
+		t.replay = append([]byte(nil), t.replay[len(t.replay)-replaySize:]...)
+	}
+
+	// The buffer is reused by the next read, the attachments share the copy.
+	chunk := append([]byte(nil), data...)
+	for attachment := range t.attachments {
+		select {
+		case attachment.queue <- chunk:
+		default:
+			t.detach(attachment)
+		}
+	}
+	return len(data), nil
+}
+
+// detach stops the attachment without sending the rest of its output, t.mu has to be held.
+func (t *runTerminal) detach(attachment *Attachment) {
+	if _, ok := t.attachments[attachment]; !ok {
+		return
+	}
+	delete(t.attachments, attachment)
+	close(attachment.stop)
+}
+
+// finish closes the terminal after the attempt exits, the attachments receive the exit code.
+func (t *runTerminal) finish(exitCode int32) {
+	t.close.Do(func() {
+		select {
+		case <-t.copied:
+		case <-time.After(outputDrainTimeout):
+		}
+		t.terminal.Close()
+		<-t.copied
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.exitCode = exitCode
+		t.exited = true
+		// The attachments send the rest of their output before they are done.
+		for attachment := range t.attachments {
+			delete(t.attachments, attachment)
+			close(attachment.queue)
+		}
+	})
+}
+
+func (t *runTerminal) attach(output io.Writer) (*Attachment, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.exited {
+		return nil, fmt.Errorf("%w: the terminal is closed", ErrRunNotFound)
+	}
+
+	attachment := &Attachment{
+		terminal: t,
+		output:   output,
+		queue:    make(chan []byte, attachmentQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if len(t.replay) > 0 {
+		attachment.queue <- append([]byte(nil), t.replay...)
+	}
+	t.attachments[attachment] = struct{}{}
+	go attachment.writeOutput()
+	return attachment, nil
+}
+
+// Attachment is a client connected to the terminal of a run.
+type Attachment struct {
+	terminal *runTerminal
+	output   io.Writer
+	// queue is closed once the attempt exits, stop once the client is detached.
+	queue chan []byte
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// writeOutput sends the output to the client, so a slow client does not hold up the terminal.
+func (a *Attachment) writeOutput() {
+	defer close(a.done)
+	for {
+		select {
+		case data, ok := <-a.queue:
+			if !ok {
+				return
+			}
+			if _, err := a.output.Write(data); err != nil {
+				a.Detach()
+				return
+			}
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// Write sends the input to the terminal.
+func (a *Attachment) Write(data []byte) (int, error) {
+	return a.terminal.terminal.MasterFile.Write(data)
+}
+
+func (a *Attachment) Resize(rows uint16, cols uint16) error {
+	attr := linux.Termios{Wz: linux.Winsize{WsRow: rows, WsCol: cols}}
+	return attr.Setwinsz(a.terminal.terminal.MasterFile)
+}
+
+// Detach disconnects the client, the process keeps running.
+func (a *Attachment) Detach() {
+	a.terminal.mu.Lock()
+	defer a.terminal.mu.Unlock()
+	a.terminal.detach(a)
+}
+
+// Done is closed once the client is detached or the attempt exits, no output is written afterwards.
+func (a *Attachment) Done() <-chan struct{} {
+	return a.done
+}
+
+// ExitCode returns the exit code of the attempt, if it has exited.
+func (a *Attachment) ExitCode() (int32, bool) {
+	a.terminal.mu.Lock()
+	defer a.terminal.mu.Unlock()
+	return a.terminal.exitCode, a.terminal.exited
+}
+
+func (s *Service) currentTerminal(name string) (*runTerminal, error) {
+	run, err := s.FindRun(name)
+	if err != nil {
+		return nil, err
+	}
+	if !run.options.Tty {
+		return nil, fmt.Errorf("%w: %s", ErrNoTerminal, name)
+	}
+	current := run.attempt()
+	if current == nil || current.terminal == nil {
+		return nil, fmt.Errorf("%w: %s is waiting for a restart", ErrRunNotFound, name)
+	}
+	return current.terminal, nil
+}
+
+// CheckAttach returns an error if the process has no terminal to attach to.
+func (s *Service) CheckAttach(name string) error {
+	_, err := s.currentTerminal(name)
+	return err
+}
+
+// Attach connects the output to the terminal of the current attempt of the run, the recent output is written first.
+func (s *Service) Attach(name string, output io.Writer, rows uint16, cols uint16) (*Attachment, error) {
+	terminal, err := s.currentTerminal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := terminal.attach(output)
+	if err != nil {
+		return nil, err
+	}
+	if rows != 0 && cols != 0 {
+		if err := attachment.Resize(rows, cols); err != nil {
+			attachment.Detach()
+			return nil, fmt.Errorf("attachment.Resize: %w", err)
+		}
+	}
+	return attachment, nil
+}
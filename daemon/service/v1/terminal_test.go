@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blockedWriter struct {
+	unblock chan struct{}
+}
+
+func (w blockedWriter) Write(data []byte) (int, error) {
+	<-w.unblock
+	return len(data), nil
+}
+
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(data)
+}
+
+func TestRunTerminal(t *testing.T) {
+	terminal, slave, err := newRunTerminal(nil)
+	require.NoError(t, err)
+	slave.Close()
+	_, _ = terminal.Write([]byte("prompt$ "))
+
+	// A client, which does not read its output, is disconnected instead of holding up the terminal.
+	slow := blockedWriter{unblock: make(chan struct{})}
+	defer close(slow.unblock)
+	slowAttachment, err := terminal.attach(slow)
+	require.NoError(t, err)
+	for i := 0; i <= attachmentQueueSize+1; i++ {
+		_, _ = terminal.Write([]byte("x"))
+	}
+	terminal.mu.Lock()
+	_, attached := terminal.attachments[slowAttachment]
+	terminal.mu.Unlock()
+	require.False(t, attached)
+
+	// The recent output is sent first, the rest of the output before the attachment is done.
+	var output syncBuffer
+	attachment, err := terminal.attach(&output)
+	require.NoError(t, err)
+	_, _ = terminal.Write([]byte("y"))
+	terminal.finish(3)
+	<-attachment.Done()
+	require.Equal(t, "prompt$ "+strings.Repeat("x", attachmentQueueSize+2)+"y", output.buffer.String())
+	code, exited := attachment.ExitCode()
+	require.True(t, exited)
+	require.Equal(t, int32(3), code)
+}
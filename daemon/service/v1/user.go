@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+)
+
+// maxIDMappings is the maximum number of lines the kernel accepts in uid_map and gid_map.
+const maxIDMappings = 340
+
+var (
+	ErrInvalidMapping = errors.New("invalid id mapping")
+)
+
+type userNamespace struct {
+	UidMappings     []syscall.SysProcIDMap
+	GidMappings     []syscall.SysProcIDMap
+	EnableSetgroups bool
+}
+
+func convertMappings(mappings []*core.IDMapping) ([]syscall.SysProcIDMap, error) {
+	if len(mappings) > maxIDMappings {
+		return nil, fmt.Errorf("%w: too many mappings", ErrInvalidMapping)
+	}
+
+	result := make([]syscall.SysProcIDMap, 0, len(mappings))
+	for i, mapping := range mappings {
+		if mapping.Size == 0 {
+			return nil, fmt.Errorf("%w: empty range", ErrInvalidMapping)
+		}
+		if uint64(mapping.ContainerId)+uint64(mapping.Size) > 1<<32-1 || uint64(mapping.HostId)+uint64(mapping.Size) > 1<<32-1 {
+			return nil, fmt.Errorf("%w: range out of bounds", ErrInvalidMapping)
+		}
+
+		for _, other := range mappings[:i] {
+			if rangesOverlap(mapping.ContainerId, other.ContainerId, mapping.Size, other.Size) {
+				return nil, fmt.Errorf("%w: overlapping container ranges", ErrInvalidMapping)
+			}
+			if rangesOverlap(mapping.HostId, other.HostId, mapping.Size, other.Size) {
+				return nil, fmt.Errorf("%w: overlapping host ranges", ErrInvalidMapping)
+			}
+		}
+
+		result = append(result, syscall.SysProcIDMap{
+			ContainerID: int(mapping.ContainerId),
+			HostID:      int(mapping.HostId),
+			Size:        int(mapping.Size),
+		})
+	}
+	return result, nil
+}
+
+func rangesOverlap(a, b, sizeA, sizeB uint32) bool {
+	return uint64(a) < uint64(b)+uint64(sizeB) && uint64(b) < uint64(a)+uint64(sizeA)
+}
+
+// newUserNamespace returns nil if the application should share the user namespace with the host.
+func newUserNamespace(options *core.RunOptions) (*userNamespace, error) {
+	if options.MapCurrentUserToRoot {
+		if len(options.UidMappings) != 0 || len(options.GidMappings) != 0 {
+			return nil, fmt.Errorf("%w: explicit mappings cannot be used with map_current_user_to_root", ErrInvalidMapping)
+		}
+
+		// An unprivileged process may only map its own gid after it denies setgroups.
+		return &userNamespace{
+			UidMappings:     []syscall.SysProcIDMap{{ContainerID: 0, HostID: syscall.Getuid(), Size: 1}},
+			GidMappings:     []syscall.SysProcIDMap{{ContainerID: 0, HostID: syscall.Getgid(), Size: 1}},
+			EnableSetgroups: syscall.Geteuid() == 0,
+		}, nil
+	}
+
+	if len(options.UidMappings) == 0 && len(options.GidMappings) == 0 {
+		return nil, nil
+	}
+	if len(options.UidMappings) == 0 || len(options.GidMappings) == 0 {
+		return nil, fmt.Errorf("%w: both uid and gid mappings are required", ErrInvalidMapping)
+	}
+
+	uidMappings, err := convertMappings(options.UidMappings)
+	if err != nil {
+		return nil, fmt.Errorf("uid mappings: %w", err)
+	}
+	gidMappings, err := convertMappings(options.GidMappings)
+	if err != nil {
+		return nil, fmt.Errorf("gid mappings: %w", err)
+	}
+
+	return &userNamespace{
+		UidMappings:     uidMappings,
+		GidMappings:     gidMappings,
+		EnableSetgroups: true,
+	}, nil
+}
+
+func (u *userNamespace) Apply(attr *syscall.SysProcAttr) {
+	attr.Cloneflags |= syscall.CLONE_NEWUSER
+	attr.UidMappings = u.UidMappings
+	attr.GidMappings = u.GidMappings
+	attr.GidMappingsEnableSetgroups = u.EnableSetgroups
+	if attr.Credential != nil {
+		attr.Credential.NoSetGroups = !u.EnableSetgroups
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/namespace"
+	"github.com/mmbednarek/fragma/pkg/process"
+	"github.com/stretchr/testify/require"
+)
+
+func startInUserNamespace(t *testing.T, options *core.RunOptions) *exec.Cmd {
+	userNs, err := newUserNamespace(options)
+	require.NoError(t, err)
+	require.NotNil(t, userNs)
+
+	cmd := exec.Command("sleep", "5")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: 0, Gid: 0},
+	}
+	userNs.Apply(cmd.SysProcAttr)
+
+	if err := cmd.Start(); err != nil {
+		t.Skipf("user namespaces are not available: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+func Test_userNamespace_ExplicitMappings(t *testing.T) {
+	cmd := startInUserNamespace(t, &core.RunOptions{
+		UidMappings: []*core.IDMapping{
+			{ContainerId: 0, HostId: 100000, Size: 1000},
+			{ContainerId: 1000, HostId: 200000, Size: 10},
+		},
+		GidMappings: []*core.IDMapping{
+			{ContainerId: 0, HostId: 300000, Size: 65536},
+		},
+	})
+
+	uidMap, err := namespace.ReadUidMap(process.Pid(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.Equal(t, []namespace.IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 1000},
+		{ContainerID: 1000, HostID: 200000, Size: 10},
+	}, uidMap)
+
+	gidMap, err := namespace.ReadGidMap(process.Pid(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.Equal(t, []namespace.IDMap{
+		{ContainerID: 0, HostID: 300000, Size: 65536},
+	}, gidMap)
+}
+
+func Test_userNamespace_MapCurrentUserToRoot(t *testing.T) {
+	cmd := startInUserNamespace(t, &core.RunOptions{
+		MapCurrentUserToRoot: true,
+	})
+
+	uidMap, err := namespace.ReadUidMap(process.Pid(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.Equal(t, []namespace.IDMap{{ContainerID: 0, HostID: syscall.Getuid(), Size: 1}}, uidMap)
+
+	gidMap, err := namespace.ReadGidMap(process.Pid(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.Equal(t, []namespace.IDMap{{ContainerID: 0, HostID: syscall.Getgid(), Size: 1}}, gidMap)
+}
+
+func Test_newUserNamespace_Invalid(t *testing.T) {
+	_, err := newUserNamespace(&core.RunOptions{
+		UidMappings: []*core.IDMapping{
+			{ContainerId: 0, HostId: 100000, Size: 1000},
+			{ContainerId: 500, HostId: 200000, Size: 10},
+		},
+		GidMappings: []*core.IDMapping{{ContainerId: 0, HostId: 100000, Size: 1}},
+	})
+	require.True(t, errors.Is(err, ErrInvalidMapping))
+
+	_, err = newUserNamespace(&core.RunOptions{
+		UidMappings: []*core.IDMapping{{ContainerId: 0, HostId: 100000, Size: 1}},
+	})
+	require.True(t, errors.Is(err, ErrInvalidMapping))
+
+	userNs, err := newUserNamespace(&core.RunOptions{})
+	require.NoError(t, err)
+	require.Nil(t, userNs)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+)
+
+// ValidateVolume checks that the image file exists and its filesystem can be mounted.
+func ValidateVolume(volume *core.Volume) error {
+	if len(volume.Path) == 0 {
+		return fmt.Errorf("%w: no path", ErrInvalidVolume)
+	}
+	info, err := os.Stat(volume.Path)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidVolume, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s is not an image file", ErrInvalidVolume, volume.Path)
+	}
+	if _, err := volumeDevice(volume, volume.ReadOnly); err != nil {
+		return fmt.Errorf("volumeDevice: %w", err)
+	}
+	return nil
+}
+
+// sameFile reports whether the path refers to the file with the given stat.
+func sameFile(path string, stat syscall.Stat_t) bool {
+	var other syscall.Stat_t
+	if err := syscall.Stat(path, &other); err != nil {
+		return false
+	}
+	return other.Dev == stat.Dev && other.Ino == stat.Ino
+}
+
+// volumeRuns returns the names of the runs, which use the image file as their root or mount it.
+func (s *Service) volumeRuns(stat syscall.Stat_t) []string {
+	s.mu.Lock()
+	runs := make([]*Run, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, run)
+	}
+	s.mu.Unlock()
+
+	var names []string
+	for _, run := range runs {
+		paths := []string{run.volume.GetPath()}
+		for _, mount := range run.options.GetMounts() {
+			if source, ok := mount.Source.(*core.Mount_Volume); ok {
+				paths = append(paths, source.Volume.GetPath())
+			}
+		}
+		for _, path := range paths {
+			if len(path) != 0 && sameFile(path, stat) {
+				names = append(names, run.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VolumeStatus reports the size of the image file, the loop devices attached to it and the runs using it.
+// The space of the filesystem is only known while the image is mounted by the daemon.
+func (s *Service) VolumeStatus(volume *core.Volume) (*core.VolumeStatus, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(volume.Path, &stat); err != nil {
+		return nil, fmt.Errorf("syscall.Stat: %w", err)
+	}
+	status := &core.VolumeStatus{
+		Size:      stat.Size,
+		Processes: s.volumeRuns(stat),
+	}
+
+	loops, err := linux.LoopList()
+	if err != nil {
+		return nil, fmt.Errorf("linux.LoopList: %w", err)
+	}
+	devices := map[deviceNumber]bool{}
+	for _, loop := range loops {
+		if loop.Info.Device != stat.Dev || loop.Info.Inode != stat.Ino {
+			continue
+		}
+		status.LoopDevices = append(status.LoopDevices, loop.Path)
+		devices[deviceNumber{major: loop.Major, minor: loop.Minor}] = true
+	}
+	if len(devices) == 0 {
+		return status, nil
+	}
+
+	mounts, err := linux.ReadMountInfo(0)
+	if err != nil {
+		return nil, fmt.Errorf("linux.ReadMountInfo: %w", err)
+	}
+	for _, mount := range mounts {
+		if !devices[deviceNumber{major: mount.Major, minor: mount.Minor}] {
+			continue
+		}
+		var statfs syscall.Statfs_t
+		if err := syscall.Statfs(mount.MountPoint, &statfs); err != nil {
+			continue
+		}
+		status.TotalSpace = int64(statfs.Blocks) * statfs.Bsize
+		status.FreeSpace = int64(statfs.Bavail) * statfs.Bsize
+		break
+	}
+	return status, nil
+}
+
+// DeleteVolume refuses with ErrVolumeInUse if any run uses the image file, otherwise its idle shared mount
+// is released and the volume is removed with remove. The runs cannot attach their volumes in the meantime,
+// so none of them starts using the image file between the check and the removal.
+func (s *Service) DeleteVolume(volume *core.Volume, remove func() error) error {
+	s.attaching.Lock()
+	defer s.attaching.Unlock()
+
+	if err := s.releaseVolume(volume); err != nil {
+		return err
+	}
+	return remove()
+}
+
+func (s *Service) releaseVolume(volume *core.Volume) error {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(volume.Path, &stat); err != nil {
+		// Nothing can use a file, which no longer exists.
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("syscall.Stat: %w", err)
+	}
+	if runs := s.volumeRuns(stat); len(runs) != 0 {
+		return fmt.Errorf("%w: %s", ErrVolumeInUse, strings.Join(runs, ", "))
+	}
+	if err := s.images.evictFile(volume.Path); err != nil {
+		return fmt.Errorf("s.images.evictFile: %w", err)
+	}
+	return nil
+}
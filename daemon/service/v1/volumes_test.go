@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeStatus(t *testing.T) {
+	path := testImage(t)
+	volume := &core.Volume{Path: path}
+	require.NoError(t, ValidateVolume(volume))
+	require.True(t, errors.Is(ValidateVolume(&core.Volume{Path: t.TempDir()}), ErrInvalidVolume))
+
+	s := NewService()
+	status, err := s.VolumeStatus(volume)
+	require.NoError(t, err)
+	require.Equal(t, int64(16<<20), status.Size)
+	require.Empty(t, status.LoopDevices)
+
+	image, err := s.images.acquire(path, linux.DeviceOptions{FsType: linux.FilesystemExt4, ReadOnly: true})
+	require.NoError(t, err)
+	s.runs["test"] = newRun("test", volume, &core.Application{}, &core.RunOptions{}, Stdio{})
+
+	status, err = s.VolumeStatus(volume)
+	require.NoError(t, err)
+	require.Equal(t, []string{image.volume.loopPath}, status.LoopDevices)
+	require.Equal(t, []string{"test"}, status.Processes)
+	require.Greater(t, status.TotalSpace, int64(0))
+	require.Greater(t, status.FreeSpace, int64(0))
+	removed := false
+	remove := func() error {
+		removed = true
+		return nil
+	}
+	require.True(t, errors.Is(s.DeleteVolume(volume, remove), ErrVolumeInUse))
+	require.False(t, removed)
+
+	// The idle shared mount is released along with the volume.
+	delete(s.runs, "test")
+	s.images.release(image)
+	require.NoError(t, s.DeleteVolume(volume, remove))
+	require.True(t, removed)
+	status, err = s.VolumeStatus(volume)
+	require.NoError(t, err)
+	require.Empty(t, status.LoopDevices)
+}
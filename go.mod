@@ -1,14 +1,16 @@
 module github.com/mmbednarek/fragma
 
-go 1.18
+go 1.20
 
 require (
 	github.com/dgraph-io/badger/v3 v3.2103.2
 	github.com/fasthttp/router v1.4.6
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
 	github.com/stretchr/testify v1.4.0
 	github.com/valyala/fasthttp v1.34.0
 	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 require (
@@ -29,10 +31,8 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/savsgio/gotils v0.0.0-20211223103454-d0aaa54c5899 // indirect
-	github.com/spf13/pflag v1.0.3 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	go.opencensus.io v0.22.5 // indirect
 	golang.org/x/net v0.0.0-20220225172249-27dd8689420f // indirect
 	golang.org/x/sys v0.0.0-20220227234510-4e6760a101f9 // indirect
-	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
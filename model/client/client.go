@@ -1,14 +1,27 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	core "github.com/mmbednarek/fragma/api/fragma/core/v1"
 	"github.com/mmbednarek/fragma/model"
 	"github.com/mmbednarek/fragma/model/repo"
+	"github.com/mmbednarek/fragma/pkg/logfile"
+	"github.com/mmbednarek/fragma/pkg/stream"
 	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type Client struct {
@@ -189,7 +202,7 @@ func (c *Client) WriteObject(obj model.Object) error {
 		return fmt.Errorf("fasthttp.Do: %w", err)
 	}
 	if resp.StatusCode() != fasthttp.StatusCreated {
-		return fmt.Errorf("invalid status code: %d", resp.StatusCode())
+		return fmt.Errorf("invalid status code: %d: %s", resp.StatusCode(), resp.Body())
 	}
 
 	return nil
@@ -213,8 +226,157 @@ func (c *Client) DeleteObject(apiName string, typeName string, name string) erro
 		return fmt.Errorf("fasthttp.Do: %w", err)
 	}
 	if resp.StatusCode() != fasthttp.StatusNoContent {
-		return fmt.Errorf("invalid status code: %d", resp.StatusCode())
+		return fmt.Errorf("invalid status code: %d: %s", resp.StatusCode(), resp.Body())
 	}
 
 	return nil
 }
+
+// postMessage sends the request to the runtime api, the response is unmarshalled if it is not nil.
+func (c *Client) postMessage(path string, request proto.Message, expectedStatus int, response proto.Message) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	data, err := protojson.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("protojson.Marshal: %w", err)
+	}
+
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.SetBody(data)
+	req.SetRequestURI(fmt.Sprintf("%s://%s/runtime/v1/%s", c.protocolPrefix(), c.host, path))
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := fasthttp.Do(req, resp); err != nil {
+		return fmt.Errorf("fasthttp.Do: %w", err)
+	}
+	if resp.StatusCode() != expectedStatus {
+		return fmt.Errorf("invalid status code: %d: %s", resp.StatusCode(), resp.Body())
+	}
+
+	if response == nil {
+		return nil
+	}
+	if err := protojson.Unmarshal(resp.Body(), response); err != nil {
+		return fmt.Errorf("protojson.Unmarshal: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) Run(request *core.RunRequest) (*core.RunResponse, error) {
+	var response core.RunResponse
+	if err := c.postMessage("run", request, fasthttp.StatusCreated, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Stop blocks until the process exits and returns its final state.
+func (c *Client) Stop(name string, request *core.StopRequest) (*core.Process, error) {
+	var process core.Process
+	if err := c.postMessage(fmt.Sprintf("processes/%s/stop", name), request, fasthttp.StatusOK, &process); err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+func (c *Client) Signal(name string, request *core.SignalRequest) error {
+	return c.postMessage(fmt.Sprintf("processes/%s/signal", name), request, fasthttp.StatusNoContent, nil)
+}
+
+func (c *Client) CollectGarbage(request *core.GcRequest) (*core.GcResponse, error) {
+	var response core.GcResponse
+	if err := c.postMessage("admin/gc", request, fasthttp.StatusOK, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// upgrade sends the request to the runtime api and upgrades the connection to a stream of frames.
+func (c *Client) upgrade(path string, request proto.Message) (*stream.Conn, error) {
+	data, err := protojson.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("protojson.Marshal: %w", err)
+	}
+
+	httpRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s://%s/runtime/v1/%s", c.protocolPrefix(), c.host, path), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequest: %w", err)
+	}
+	httpRequest.Header.Set("Connection", "Upgrade")
+	httpRequest.Header.Set("Upgrade", stream.Protocol)
+
+	// The connection is upgraded, so it cannot be taken from a pool of the http clients.
+	conn, err := net.Dial("tcp", c.host)
+	if err != nil {
+		return nil, fmt.Errorf("net.Dial: %w", err)
+	}
+	if err := httpRequest.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("httpRequest.Write: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, httpRequest)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("http.ReadResponse: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("invalid status code: %d: %s", resp.StatusCode, body)
+	}
+
+	return stream.NewConn(reader, conn), nil
+}
+
+// Exec starts a process inside a running process, the returned connection carries its stdio.
+func (c *Client) Exec(name string, request *core.ExecRequest) (*stream.Conn, error) {
+	return c.upgrade(fmt.Sprintf("processes/%s/exec", name), request)
+}
+
+// Attach connects to the terminal of a running process, closing the connection detaches from it.
+func (c *Client) Attach(name string, request *core.AttachRequest) (*stream.Conn, error) {
+	return c.upgrade(fmt.Sprintf("processes/%s/attach", name), request)
+}
+
+// Logs reads the records of the process, with follow it blocks until the process exits.
+func (c *Client) Logs(name string, options logfile.ReadOptions, fn func(logfile.Record) error) error {
+	query := url.Values{}
+	if options.Follow {
+		query.Set("follow", "true")
+	}
+	if !options.Since.IsZero() {
+		query.Set("since", options.Since.Format(time.RFC3339Nano))
+	}
+	if options.Tail >= 0 {
+		query.Set("tail", strconv.Itoa(options.Tail))
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s://%s/runtime/v1/processes/%s/logs?%s", c.protocolPrefix(), c.host, name, query.Encode()))
+	if err != nil {
+		return fmt.Errorf("http.Get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("invalid status code: %d: %s", resp.StatusCode, body)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var record logfile.Record
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decoder.Decode: %w", err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
@@ -17,6 +17,12 @@ type Controller interface {
 	OnRead(obj *Object)
 }
 
+// Validator is implemented by the controllers, which can refuse a change before it is stored.
+type Validator interface {
+	ValidateUpdate(obj *Object) error
+	ValidateDelete(typeName string, name string) error
+}
+
 type CrudService[TStore Storage] struct {
 	controllers []Controller
 	storage     TStore
@@ -31,6 +37,14 @@ func (s *CrudService[TStore]) AddController(listener Controller) {
 }
 
 func (s *CrudService[TStore]) Update(obj *Object) error {
+	for _, cont := range s.controllers {
+		if validator, ok := cont.(Validator); ok {
+			if err := validator.ValidateUpdate(obj); err != nil {
+				return fmt.Errorf("validator.ValidateUpdate: %w", err)
+			}
+		}
+	}
+
 	if err := s.storage.WriteObject(obj); err != nil {
 		return fmt.Errorf("s.storage.WriteObject: %w", err)
 	}
@@ -55,6 +69,14 @@ func (s *CrudService[TStore]) Read(typeName string, name string) (Object, error)
 }
 
 func (s *CrudService[TStore]) Delete(typeName string, name string) error {
+	for _, cont := range s.controllers {
+		if validator, ok := cont.(Validator); ok {
+			if err := validator.ValidateDelete(typeName, name); err != nil {
+				return fmt.Errorf("validator.ValidateDelete: %w", err)
+			}
+		}
+	}
+
 	if err := s.storage.RemoveObject(typeName, name); err != nil {
 		return fmt.Errorf("s.storage.RemoveObject: %w", err)
 	}
@@ -13,6 +13,9 @@ import (
 
 var (
 	ErrObjectNotFound = errors.New("object not found")
+	// ErrInvalidObject and ErrObjectInUse are returned by the validators, which refuse a change.
+	ErrInvalidObject = errors.New("invalid object")
+	ErrObjectInUse   = errors.New("object is in use")
 )
 
 type Spec struct {
@@ -16,11 +16,12 @@ func Test_ObjectMarshall(t *testing.T) {
 	}
 
 	obj := Object{
-		Version: "core_v1",
-		Name:    "test",
-		Kind:    "fragma.core_v1.core.Application",
-		Labels:  map[string]string{"label": "something"},
-		Spec:    Spec{&app},
+		Kind: "fragma.core.v1.Application",
+		Metadata: Metadata{
+			Name:   "test",
+			Labels: map[string]string{"label": "something"},
+		},
+		Spec: Spec{Message: &app},
 	}
 
 	bytes, err := json.Marshal(obj)
@@ -29,7 +30,7 @@ func Test_ObjectMarshall(t *testing.T) {
 	fmt.Println(string(bytes))
 
 	obj2 := Object{
-		Spec: Spec{&core_v1.Application{}},
+		Spec: Spec{Message: &core_v1.Application{}},
 	}
 	require.NoError(t, json.Unmarshal(bytes, &obj2))
 
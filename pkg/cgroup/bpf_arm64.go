@@ -0,0 +1,3 @@
+package cgroup
+
+const SYS_BPF = 280
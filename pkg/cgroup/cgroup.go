@@ -0,0 +1,259 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	CGROUP2_SUPER_MAGIC = 0x63677270
+
+	// FragmaGroup is the parent of all the cgroups created by fragma.
+	FragmaGroup = "fragma"
+)
+
+var (
+	ErrNotCGroup2        = errors.New("cgroup v2 hierarchy not found")
+	ErrControllerMissing = errors.New("cgroup controller is not enabled")
+)
+
+var mountPoints = []string{
+	"/sys/fs/cgroup",
+	"/sys/fs/cgroup/unified",
+}
+
+type IOLimit struct {
+	Major     uint32
+	Minor     uint32
+	ReadBps   uint64
+	WriteBps  uint64
+	ReadIOps  uint64
+	WriteIOps uint64
+}
+
+type Limits struct {
+	MemoryMax     int64
+	MemorySwapMax int64
+	CPUQuota      int64
+	CPUPeriod     int64
+	CPUWeight     uint64
+	PidsMax       int64
+	IO            []IOLimit
+}
+
+type CGroup struct {
+	Name string
+	Path string
+}
+
+// FindRoot returns the mount point of the cgroup v2 hierarchy.
+func FindRoot() (string, error) {
+	for _, path := range mountPoints {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			continue
+		}
+		if stat.Type == CGROUP2_SUPER_MAGIC {
+			return path, nil
+		}
+	}
+	return "", ErrNotCGroup2
+}
+
+// New creates a leaf cgroup under the fragma group and enables the controllers for it.
+func New(name string, controllers []string) (CGroup, error) {
+	root, err := FindRoot()
+	if err != nil {
+		return CGroup{}, err
+	}
+
+	parent := filepath.Join(root, FragmaGroup)
+	if err := os.Mkdir(parent, 0755); err != nil && !os.IsExist(err) {
+		return CGroup{}, err
+	}
+
+	if err := enableControllers(root, controllers); err != nil {
+		return CGroup{}, fmt.Errorf("enableControllers: %w", err)
+	}
+	if err := enableControllers(parent, controllers); err != nil {
+		return CGroup{}, fmt.Errorf("enableControllers: %w", err)
+	}
+
+	path := filepath.Join(parent, name)
+	if err := os.Mkdir(path, 0755); err != nil {
+		return CGroup{}, err
+	}
+
+	return CGroup{Name: name, Path: path}, nil
+}
+
+func readControllers(path string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(path, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// enableControllers enables the controllers for the children of the cgroup, the other ones are left as they are.
+func enableControllers(path string, controllers []string) error {
+	if len(controllers) == 0 {
+		return nil
+	}
+	available, err := readControllers(path)
+	if err != nil {
+		return err
+	}
+	enabled := map[string]bool{}
+	for _, controller := range available {
+		enabled[controller] = true
+	}
+
+	changes := make([]string, 0, len(controllers))
+	for _, controller := range controllers {
+		if !enabled[controller] {
+			return fmt.Errorf("%w: %s", ErrControllerMissing, controller)
+		}
+		changes = append(changes, "+"+controller)
+	}
+
+	return os.WriteFile(filepath.Join(path, "cgroup.subtree_control"), []byte(strings.Join(changes, " ")), 0644)
+}
+
+func (c CGroup) write(file string, value string) error {
+	f, err := os.OpenFile(filepath.Join(c.Path, file), os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrControllerMissing, file)
+		}
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(value); err != nil {
+		return fmt.Errorf("could not write %s: %w", file, err)
+	}
+	return nil
+}
+
+func formatMax(value int64) string {
+	if value < 0 {
+		return "max"
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// Controllers returns the controllers, which have to be enabled to apply the limits.
+func (l Limits) Controllers() []string {
+	var controllers []string
+	if l.MemoryMax != 0 || l.MemorySwapMax != 0 {
+		controllers = append(controllers, "memory")
+	}
+	if l.CPUQuota != 0 || l.CPUPeriod != 0 || l.CPUWeight != 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if l.PidsMax != 0 {
+		controllers = append(controllers, "pids")
+	}
+	if len(l.IO) != 0 {
+		controllers = append(controllers, "io")
+	}
+	return controllers
+}
+
+// SetLimits applies the limits to the cgroup, zero values are left unchanged.
+func (c CGroup) SetLimits(limits Limits) error {
+	if limits.MemoryMax != 0 {
+		if err := c.write("memory.max", formatMax(limits.MemoryMax)); err != nil {
+			return err
+		}
+	}
+	if limits.MemorySwapMax != 0 {
+		if err := c.write("memory.swap.max", formatMax(limits.MemorySwapMax)); err != nil {
+			return err
+		}
+	}
+	if limits.CPUQuota != 0 || limits.CPUPeriod != 0 {
+		period := limits.CPUPeriod
+		if period == 0 {
+			period = 100000
+		}
+		quota := "max"
+		if limits.CPUQuota > 0 {
+			quota = strconv.FormatInt(limits.CPUQuota, 10)
+		}
+		if err := c.write("cpu.max", fmt.Sprintf("%s %d", quota, period)); err != nil {
+			return err
+		}
+	}
+	if limits.CPUWeight != 0 {
+		if err := c.write("cpu.weight", strconv.FormatUint(limits.CPUWeight, 10)); err != nil {
+			return err
+		}
+	}
+	if limits.PidsMax != 0 {
+		if err := c.write("pids.max", formatMax(limits.PidsMax)); err != nil {
+			return err
+		}
+	}
+	for _, io := range limits.IO {
+		if err := c.write("io.max", io.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l IOLimit) String() string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("%d:%d", l.Major, l.Minor))
+	if l.ReadBps != 0 {
+		b.WriteString(fmt.Sprintf(" rbps=%d", l.ReadBps))
+	}
+	if l.WriteBps != 0 {
+		b.WriteString(fmt.Sprintf(" wbps=%d", l.WriteBps))
+	}
+	if l.ReadIOps != 0 {
+		b.WriteString(fmt.Sprintf(" riops=%d", l.ReadIOps))
+	}
+	if l.WriteIOps != 0 {
+		b.WriteString(fmt.Sprintf(" wiops=%d", l.WriteIOps))
+	}
+	return b.String()
+}
+
+// Open returns a file descriptor of the cgroup directory, suitable for CLONE_INTO_CGROUP.
+func (c CGroup) Open() (int, error) {
+	return syscall.Open(c.Path, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+}
+
+// Kill kills all the processes remaining in the cgroup.
+func (c CGroup) Kill() error {
+	return c.write("cgroup.kill", "1")
+}
+
+// Remove kills remaining processes and removes the cgroup. The kernel
+// refuses to remove a cgroup until all of its processes have exited,
+// so the removal is retried for a short while.
+func (c CGroup) Remove() error {
+	_ = c.Kill()
+
+	var err error
+	for i := 0; i < 50; i++ {
+		err = syscall.Rmdir(c.Path)
+		if err == nil || err == syscall.ENOENT {
+			return nil
+		}
+		if err != syscall.EBUSY {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package cgroup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMax(t *testing.T) {
+	tests := []struct {
+		value    int64
+		expected string
+	}{
+		{value: -1, expected: "max"},
+		{value: 1, expected: "1"},
+		{value: 512 << 20, expected: "536870912"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, formatMax(test.value))
+	}
+}
+
+func TestIOLimit_String(t *testing.T) {
+	tests := []struct {
+		limit    IOLimit
+		expected string
+	}{
+		{limit: IOLimit{Major: 8, Minor: 0}, expected: "8:0"},
+		{limit: IOLimit{Major: 8, Minor: 16, ReadBps: 1048576}, expected: "8:16 rbps=1048576"},
+		{limit: IOLimit{Major: 259, Minor: 1, WriteBps: 2, ReadIOps: 3, WriteIOps: 4}, expected: "259:1 wbps=2 riops=3 wiops=4"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.limit.String())
+	}
+}
+
+// fakeGroup creates the interface files of a cgroup in a directory.
+func fakeGroup(t *testing.T, files ...string) CGroup {
+	path := t.TempDir()
+	for _, file := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), nil, 0644))
+	}
+	return CGroup{Name: "test", Path: path}
+}
+
+func readFile(t *testing.T, group CGroup, file string) string {
+	data, err := os.ReadFile(filepath.Join(group.Path, file))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCGroup_SetLimits(t *testing.T) {
+	group := fakeGroup(t, "memory.max", "memory.swap.max", "cpu.max", "cpu.weight", "pids.max", "io.max")
+	limits := Limits{
+		MemoryMax:     64 << 20,
+		MemorySwapMax: -1,
+		CPUQuota:      50000,
+		CPUWeight:     200,
+		PidsMax:       -1,
+		IO:            []IOLimit{{Major: 8, Minor: 0, WriteIOps: 100}},
+	}
+	require.NoError(t, group.SetLimits(limits))
+	require.Equal(t, "67108864", readFile(t, group, "memory.max"))
+	require.Equal(t, "max", readFile(t, group, "memory.swap.max"))
+	require.Equal(t, "50000 100000", readFile(t, group, "cpu.max"))
+	require.Equal(t, "200", readFile(t, group, "cpu.weight"))
+	require.Equal(t, "max", readFile(t, group, "pids.max"))
+	require.Equal(t, "8:0 wiops=100", readFile(t, group, "io.max"))
+
+	// Only the period is set, the quota stays unlimited.
+	require.NoError(t, group.SetLimits(Limits{CPUPeriod: 20000}))
+	require.Equal(t, "max 20000", readFile(t, group, "cpu.max"))
+
+	err := fakeGroup(t).SetLimits(Limits{PidsMax: 10})
+	require.True(t, errors.Is(err, ErrControllerMissing))
+}
+
+func TestEnableControllers(t *testing.T) {
+	group := fakeGroup(t, "cgroup.subtree_control")
+	require.NoError(t, os.WriteFile(filepath.Join(group.Path, "cgroup.controllers"), []byte("cpuset cpu io memory hugetlb pids rdma misc\n"), 0644))
+
+	limits := Limits{MemoryMax: 1 << 20, PidsMax: 10}
+	require.Equal(t, []string{"memory", "pids"}, limits.Controllers())
+	require.NoError(t, enableControllers(group.Path, limits.Controllers()))
+	require.Equal(t, "+memory +pids", readFile(t, group, "cgroup.subtree_control"))
+
+	err := enableControllers(group.Path, []string{"freezer"})
+	require.True(t, errors.Is(err, ErrControllerMissing))
+	require.Empty(t, Limits{}.Controllers())
+}
@@ -0,0 +1,198 @@
+package cgroup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	BPF_PROG_LOAD   = 5
+	BPF_PROG_ATTACH = 8
+
+	BPF_PROG_TYPE_CGROUP_DEVICE = 15
+	BPF_CGROUP_DEVICE           = 6
+
+	BPF_DEVCG_ACC_MKNOD = 1
+	BPF_DEVCG_ACC_READ  = 2
+	BPF_DEVCG_ACC_WRITE = 4
+
+	BPF_DEVCG_DEV_BLOCK = 1
+	BPF_DEVCG_DEV_CHAR  = 2
+)
+
+// The eBPF instructions used by the device program.
+const (
+	bpfLdxMemW  = 0x61 // dst = *(u32 *)(src + off)
+	bpfAndImm   = 0x57 // dst &= imm
+	bpfRshImm   = 0x77 // dst >>= imm
+	bpfMovImm   = 0xb7 // dst = imm
+	bpfMovReg   = 0xbf // dst = src
+	bpfJneImm   = 0x55 // if dst != imm goto pc + off
+	bpfExit     = 0x95
+	bpfInsnSize = 8
+)
+
+// AnyMinor matches all the minor numbers of the major number.
+const AnyMinor = -1
+
+// DeviceRule allows the access to a device.
+type DeviceRule struct {
+	// Type is either BPF_DEVCG_DEV_CHAR or BPF_DEVCG_DEV_BLOCK.
+	Type  uint32
+	Major uint32
+	// Minor is either a minor number or AnyMinor.
+	Minor int64
+	// Access is a mask of the BPF_DEVCG_ACC_* flags.
+	Access uint32
+}
+
+type bpfInsn struct {
+	code uint8
+	dst  uint8
+	src  uint8
+	off  int16
+	imm  int32
+}
+
+func (i bpfInsn) encode(buff []byte) {
+	buff[0] = i.code
+	buff[1] = i.dst | i.src<<4
+	binary.LittleEndian.PutUint16(buff[2:], uint16(i.off))
+	binary.LittleEndian.PutUint32(buff[4:], uint32(i.imm))
+}
+
+// deviceProgram builds a program, which allows the access matched by any of the rules and denies everything else.
+// The context is struct bpf_cgroup_dev_ctx { u32 access_type; u32 major; u32 minor; },
+// where access_type holds the access flags in the upper and the device type in the lower 16 bits.
+func deviceProgram(rules []DeviceRule) []bpfInsn {
+	program := []bpfInsn{
+		{code: bpfLdxMemW, dst: 2, src: 1, off: 0},
+		{code: bpfAndImm, dst: 2, imm: 0xffff},
+		{code: bpfLdxMemW, dst: 3, src: 1, off: 0},
+		{code: bpfRshImm, dst: 3, imm: 16},
+		{code: bpfLdxMemW, dst: 4, src: 1, off: 4},
+		{code: bpfLdxMemW, dst: 5, src: 1, off: 8},
+	}
+
+	for _, rule := range rules {
+		var block []bpfInsn
+		block = append(block, bpfInsn{code: bpfJneImm, dst: 2, imm: int32(rule.Type)})
+		block = append(block, bpfInsn{code: bpfJneImm, dst: 4, imm: int32(rule.Major)})
+		if rule.Minor != AnyMinor {
+			block = append(block, bpfInsn{code: bpfJneImm, dst: 5, imm: int32(rule.Minor)})
+		}
+		// The requested access has to be a subset of the allowed one.
+		block = append(block,
+			bpfInsn{code: bpfMovReg, dst: 6, src: 3},
+			bpfInsn{code: bpfAndImm, dst: 6, imm: int32(^rule.Access & 0xffff)},
+			bpfInsn{code: bpfJneImm, dst: 6, imm: 0},
+			bpfInsn{code: bpfMovImm, dst: 0, imm: 1},
+			bpfInsn{code: bpfExit},
+		)
+		// The failed comparisons skip the rest of the rule.
+		for i := range block {
+			if block[i].code == bpfJneImm {
+				block[i].off = int16(len(block) - i - 1)
+			}
+		}
+		program = append(program, block...)
+	}
+
+	return append(program,
+		bpfInsn{code: bpfMovImm, dst: 0, imm: 0},
+		bpfInsn{code: bpfExit},
+	)
+}
+
+type bpfProgLoadAttr struct {
+	progType    uint32
+	insnCnt     uint32
+	insns       uint64
+	license     uint64
+	logLevel    uint32
+	logSize     uint32
+	logBuf      uint64
+	kernVersion uint32
+	progFlags   uint32
+}
+
+type bpfProgAttachAttr struct {
+	targetFd    uint32
+	attachBpfFd uint32
+	attachType  uint32
+	attachFlags uint32
+}
+
+func bpf(cmd uintptr, attr unsafe.Pointer, size uintptr) (int, error) {
+	fd, _, errno := syscall.Syscall(SYS_BPF, cmd, uintptr(attr), size)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
+
+func loadDeviceProgram(rules []DeviceRule) (int, error) {
+	program := deviceProgram(rules)
+	insns := make([]byte, len(program)*bpfInsnSize)
+	for i, insn := range program {
+		insn.encode(insns[i*bpfInsnSize:])
+	}
+	license := []byte("GPL\x00")
+	verifierLog := make([]byte, 64<<10)
+
+	attr := bpfProgLoadAttr{
+		progType: BPF_PROG_TYPE_CGROUP_DEVICE,
+		insnCnt:  uint32(len(program)),
+		insns:    uint64(uintptr(unsafe.Pointer(&insns[0]))),
+		license:  uint64(uintptr(unsafe.Pointer(&license[0]))),
+		logLevel: 1,
+		logSize:  uint32(len(verifierLog)),
+		logBuf:   uint64(uintptr(unsafe.Pointer(&verifierLog[0]))),
+	}
+	fd, err := bpf(BPF_PROG_LOAD, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+	// The buffers are only referenced by the addresses in attr.
+	runtime.KeepAlive(insns)
+	runtime.KeepAlive(license)
+	if err != nil {
+		return -1, fmt.Errorf("bpf prog load: %w: %s", err, cString(verifierLog))
+	}
+	return fd, nil
+}
+
+func cString(buff []byte) string {
+	for i, b := range buff {
+		if b == 0 {
+			return string(buff[:i])
+		}
+	}
+	return string(buff)
+}
+
+// AllowDevices attaches a device program to the cgroup, the processes of the cgroup can only
+// create, read and write the devices allowed by the rules. The program stays attached until the cgroup is removed.
+func (c CGroup) AllowDevices(rules []DeviceRule) error {
+	group, err := syscall.Open(c.Path, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("could not open cgroup: %w", err)
+	}
+	defer syscall.Close(group)
+
+	program, err := loadDeviceProgram(rules)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(program)
+
+	attr := bpfProgAttachAttr{
+		targetFd:    uint32(group),
+		attachBpfFd: uint32(program),
+		attachType:  BPF_CGROUP_DEVICE,
+	}
+	if _, err := bpf(BPF_PROG_ATTACH, unsafe.Pointer(&attr), unsafe.Sizeof(attr)); err != nil {
+		return fmt.Errorf("bpf prog attach: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package cgroup
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceProgram(t *testing.T) {
+	fd, err := loadDeviceProgram([]DeviceRule{
+		{Type: BPF_DEVCG_DEV_CHAR, Major: 1, Minor: 3, Access: BPF_DEVCG_ACC_MKNOD | BPF_DEVCG_ACC_READ | BPF_DEVCG_ACC_WRITE},
+		{Type: BPF_DEVCG_DEV_CHAR, Major: 136, Minor: AnyMinor, Access: BPF_DEVCG_ACC_READ | BPF_DEVCG_ACC_WRITE},
+	})
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOSYS) {
+		t.Skip("bpf programs cannot be loaded")
+	}
+	// The program is accepted by the verifier.
+	require.NoError(t, err)
+	require.NoError(t, syscall.Close(fd))
+}
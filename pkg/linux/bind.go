@@ -0,0 +1,209 @@
+package linux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxSymlinks is the limit of symlinks followed while resolving a single path, same as in the kernel.
+const maxSymlinks = 40
+
+var ErrInvalidMountPath = errors.New("invalid mount path")
+
+type MountKind string
+
+const (
+	MountBind   MountKind = "bind"
+	MountTmpfs  MountKind = "tmpfs"
+	MountProc   MountKind = "proc"
+	MountSysfs  MountKind = "sysfs"
+	MountDevpts MountKind = "devpts"
+	MountMqueue MountKind = "mqueue"
+)
+
+// MountSpec describes a mount made inside the rootfs of a container.
+type MountSpec struct {
+	Kind        MountKind `json:"kind"`
+	Source      string    `json:"source,omitempty"`
+	Destination string    `json:"destination"`
+	ReadOnly    bool      `json:"readOnly,omitempty"`
+	Recursive   bool      `json:"recursive,omitempty"`
+	Size        uint64    `json:"size,omitempty"`
+	Mode        uint32    `json:"mode,omitempty"`
+	// AllowDevices permits the device nodes on a tmpfs, it is set for the /dev of a container.
+	AllowDevices bool `json:"allowDevices,omitempty"`
+}
+
+// ValidateMountPath accepts absolute paths without .. components.
+func ValidateMountPath(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s is not absolute", ErrInvalidMountPath, path)
+	}
+	for _, part := range strings.Split(path, "/") {
+		if part == ".." {
+			return fmt.Errorf("%w: %s contains ..", ErrInvalidMountPath, path)
+		}
+	}
+	return nil
+}
+
+// ResolveInRoot resolves the path as if root was the root directory. Symlinks are
+// followed, but neither them nor .. can lead outside of root. Missing components are kept as they are.
+func ResolveInRoot(root string, path string) (string, error) {
+	resolved := "/"
+	remaining := path
+	links := 0
+
+	for len(remaining) > 0 {
+		var part string
+		part, remaining, _ = strings.Cut(strings.TrimLeft(remaining, "/"), "/")
+		if part == "" || part == "." {
+			continue
+		}
+		if part == ".." {
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if err != nil {
+			if os.IsNotExist(err) {
+				resolved = next
+				continue
+			}
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", Error{Errno: syscall.ELOOP}
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		remaining = target + "/" + remaining
+	}
+
+	return filepath.Join(root, resolved), nil
+}
+
+// createMountPoint creates a directory, or an empty file if the source is not a directory.
+func createMountPoint(path string, directory bool) error {
+	if _, err := os.Lstat(path); err == nil {
+		return nil
+	}
+	if directory {
+		return os.MkdirAll(path, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Apply mounts the spec under rootfs. In case of recursive bind mounts only the top mount is made read-only.
+func (s MountSpec) Apply(rootfs string) error {
+	if err := ValidateMountPath(s.Destination); err != nil {
+		return err
+	}
+	destination, err := ResolveInRoot(rootfs, s.Destination)
+	if err != nil {
+		return fmt.Errorf("could not resolve %s: %w", s.Destination, err)
+	}
+
+	switch s.Kind {
+	case MountBind:
+		info, err := os.Stat(s.Source)
+		if err != nil {
+			return err
+		}
+		if err := createMountPoint(destination, info.IsDir()); err != nil {
+			return fmt.Errorf("could not create mount point: %w", err)
+		}
+
+		flags := uintptr(syscall.MS_BIND)
+		if s.Recursive {
+			flags |= syscall.MS_REC
+		}
+		if err := syscall.Mount(s.Source, destination, "", flags, ""); err != nil {
+			return fmt.Errorf("could not bind mount %s: %w", s.Source, err)
+		}
+
+		if s.ReadOnly {
+			var stat syscall.Statfs_t
+			if err := syscall.Statfs(s.Source, &stat); err != nil {
+				return fmt.Errorf("could not stat %s: %w", s.Source, err)
+			}
+			// The read-only flag of a bind mount can only be set by a remount, which keeps the locked flags of the source.
+			flags := uintptr(syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY) | uintptr(stat.Flags)&lockedFlags
+			if err := syscall.Mount("", destination, "", flags, ""); err != nil {
+				return fmt.Errorf("could not remount %s read-only: %w", s.Destination, err)
+			}
+		}
+	case MountTmpfs:
+		if err := createMountPoint(destination, true); err != nil {
+			return fmt.Errorf("could not create mount point: %w", err)
+		}
+
+		mode := s.Mode
+		if mode == 0 {
+			mode = 01777
+		}
+		options := fmt.Sprintf("mode=%o", mode)
+		if s.Size != 0 {
+			options += fmt.Sprintf(",size=%d", s.Size)
+		}
+
+		flags := uintptr(syscall.MS_NOSUID)
+		if !s.AllowDevices {
+			flags |= syscall.MS_NODEV
+		}
+		if s.ReadOnly {
+			flags |= syscall.MS_RDONLY
+		}
+		if err := syscall.Mount("tmpfs", destination, "tmpfs", flags, options); err != nil {
+			return fmt.Errorf("could not mount tmpfs: %w", err)
+		}
+	case MountProc, MountSysfs, MountDevpts, MountMqueue:
+		if err := createMountPoint(destination, true); err != nil {
+			return fmt.Errorf("could not create mount point: %w", err)
+		}
+
+		flags := uintptr(syscall.MS_NOSUID | syscall.MS_NOEXEC)
+		options := ""
+		if s.Kind == MountDevpts {
+			// The ptmx of a new instance is only accessible to root by default.
+			options = "newinstance,ptmxmode=0666"
+		} else {
+			flags |= syscall.MS_NODEV
+		}
+		if s.ReadOnly {
+			flags |= syscall.MS_RDONLY
+		}
+		if err := syscall.Mount(string(s.Kind), destination, string(s.Kind), flags, options); err != nil {
+			return fmt.Errorf("could not mount %s: %w", s.Kind, err)
+		}
+	default:
+		return fmt.Errorf("unknown mount kind: %s", s.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package linux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMountPath(t *testing.T) {
+	require.NoError(t, ValidateMountPath("/var/lib/data"))
+	require.True(t, errors.Is(ValidateMountPath("var/lib"), ErrInvalidMountPath))
+	require.True(t, errors.Is(ValidateMountPath("/var/../../etc"), ErrInvalidMountPath))
+}
+
+func TestResolveInRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "var/lib"), 0755))
+	require.NoError(t, os.Symlink("/etc", filepath.Join(root, "var/abs")))
+	require.NoError(t, os.Symlink("../../../..", filepath.Join(root, "var/lib/up")))
+	require.NoError(t, os.Symlink("lib", filepath.Join(root, "var/rel")))
+	require.NoError(t, os.Symlink("loop", filepath.Join(root, "loop")))
+
+	cases := map[string]string{
+		"/var/lib/data":     "/var/lib/data",
+		"/var/abs/passwd":   "/etc/passwd",
+		"/var/lib/up/etc":   "/etc",
+		"/var/rel/data":     "/var/lib/data",
+		"/../../etc/shadow": "/etc/shadow",
+	}
+	for path, expected := range cases {
+		resolved, err := ResolveInRoot(root, path)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(root, expected), resolved, path)
+	}
+
+	_, err := ResolveInRoot(root, "/loop/data")
+	require.Error(t, err)
+}
+
+func TestMountSpec_ApplyReadOnlyBind(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("mounting requires root")
+	}
+	source := t.TempDir()
+	require.NoError(t, syscall.Mount("tmpfs", source, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, ""))
+	defer syscall.Unmount(source, syscall.MNT_DETACH)
+
+	rootfs := t.TempDir()
+	spec := MountSpec{Kind: MountBind, Source: source, Destination: "/data", ReadOnly: true}
+	require.NoError(t, spec.Apply(rootfs))
+	destination := filepath.Join(rootfs, "data")
+	defer syscall.Unmount(destination, syscall.MNT_DETACH)
+
+	// The remount keeps the flags of the source, which a user namespace could not clear.
+	var stat syscall.Statfs_t
+	require.NoError(t, syscall.Statfs(destination, &stat))
+	require.Equal(t, int64(syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV), stat.Flags&(syscall.MS_RDONLY|lockedFlags))
+}
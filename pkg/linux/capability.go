@@ -0,0 +1,324 @@
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+type Capability int
+
+const (
+	CAP_CHOWN              Capability = 0
+	CAP_DAC_OVERRIDE       Capability = 1
+	CAP_DAC_READ_SEARCH    Capability = 2
+	CAP_FOWNER             Capability = 3
+	CAP_FSETID             Capability = 4
+	CAP_KILL               Capability = 5
+	CAP_SETGID             Capability = 6
+	CAP_SETUID             Capability = 7
+	CAP_SETPCAP            Capability = 8
+	CAP_LINUX_IMMUTABLE    Capability = 9
+	CAP_NET_BIND_SERVICE   Capability = 10
+	CAP_NET_BROADCAST      Capability = 11
+	CAP_NET_ADMIN          Capability = 12
+	CAP_NET_RAW            Capability = 13
+	CAP_IPC_LOCK           Capability = 14
+	CAP_IPC_OWNER          Capability = 15
+	CAP_SYS_MODULE         Capability = 16
+	CAP_SYS_RAWIO          Capability = 17
+	CAP_SYS_CHROOT         Capability = 18
+	CAP_SYS_PTRACE         Capability = 19
+	CAP_SYS_PACCT          Capability = 20
+	CAP_SYS_ADMIN          Capability = 21
+	CAP_SYS_BOOT           Capability = 22
+	CAP_SYS_NICE           Capability = 23
+	CAP_SYS_RESOURCE       Capability = 24
+	CAP_SYS_TIME           Capability = 25
+	CAP_SYS_TTY_CONFIG     Capability = 26
+	CAP_MKNOD              Capability = 27
+	CAP_LEASE              Capability = 28
+	CAP_AUDIT_WRITE        Capability = 29
+	CAP_AUDIT_CONTROL      Capability = 30
+	CAP_SETFCAP            Capability = 31
+	CAP_MAC_OVERRIDE       Capability = 32
+	CAP_MAC_ADMIN          Capability = 33
+	CAP_SYSLOG             Capability = 34
+	CAP_WAKE_ALARM         Capability = 35
+	CAP_BLOCK_SUSPEND      Capability = 36
+	CAP_AUDIT_READ         Capability = 37
+	CAP_PERFMON            Capability = 38
+	CAP_BPF                Capability = 39
+	CAP_CHECKPOINT_RESTORE Capability = 40
+)
+
+const (
+	PR_SET_KEEPCAPS          = 8
+	PR_CAPBSET_DROP          = 24
+	PR_SET_SECUREBITS        = 28
+	PR_SET_NO_NEW_PRIVS      = 38
+	PR_GET_NO_NEW_PRIVS      = 39
+	PR_CAP_AMBIENT           = 47
+	PR_CAP_AMBIENT_RAISE     = 2
+	PR_CAP_AMBIENT_CLEAR_ALL = 4
+
+	SECBIT_NOROOT        = 1 << 0
+	SECBIT_NOROOT_LOCKED = 1 << 1
+
+	_LINUX_CAPABILITY_VERSION_3 = 0x20080522
+)
+
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+func (c Capability) String() string {
+	if c < 0 || int(c) >= len(capabilityNames) {
+		return fmt.Sprintf("CAP_%d", int(c))
+	}
+	return capabilityNames[c]
+}
+
+// CapabilityFromName accepts names with or without the CAP_ prefix, in any case.
+func CapabilityFromName(name string) (Capability, error) {
+	upper := strings.ToUpper(name)
+	if !strings.HasPrefix(upper, "CAP_") {
+		upper = "CAP_" + upper
+	}
+	for i, capName := range capabilityNames {
+		if capName == upper {
+			return Capability(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown capability: %s", name)
+}
+
+// CapabilitySet is a bitmask of capabilities, as presented in /proc/<pid>/status.
+type CapabilitySet uint64
+
+func NewCapabilitySet(caps ...Capability) CapabilitySet {
+	var set CapabilitySet
+	for _, c := range caps {
+		set |= 1 << uint(c)
+	}
+	return set
+}
+
+func ParseCapabilitySet(names []string) (CapabilitySet, error) {
+	var set CapabilitySet
+	for _, name := range names {
+		c, err := CapabilityFromName(name)
+		if err != nil {
+			return 0, err
+		}
+		set |= 1 << uint(c)
+	}
+	return set, nil
+}
+
+func (s CapabilitySet) Has(c Capability) bool {
+	return s&(1<<uint(c)) != 0
+}
+
+func (s CapabilitySet) Names() []string {
+	var result []string
+	for i := range capabilityNames {
+		if s.Has(Capability(i)) {
+			result = append(result, capabilityNames[i])
+		}
+	}
+	return result
+}
+
+// Capabilities are the capability sets of a process.
+type Capabilities struct {
+	Inheritable CapabilitySet
+	Permitted   CapabilitySet
+	Effective   CapabilitySet
+	Bounding    CapabilitySet
+	Ambient     CapabilitySet
+}
+
+// ReadCapabilities reads the capability sets of the process from /proc/<pid>/status.
+func ReadCapabilities(pid int) (Capabilities, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return Capabilities{}, err
+	}
+	defer file.Close()
+
+	var result Capabilities
+	fields := map[string]*CapabilitySet{
+		"CapInh": &result.Inheritable,
+		"CapPrm": &result.Permitted,
+		"CapEff": &result.Effective,
+		"CapBnd": &result.Bounding,
+		"CapAmb": &result.Ambient,
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		set, ok := fields[key]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return Capabilities{}, fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		*set = CapabilitySet(parsed)
+	}
+	if err := scanner.Err(); err != nil {
+		return Capabilities{}, err
+	}
+
+	return result, nil
+}
+
+// LastCapability returns the highest capability supported by the kernel.
+func LastCapability() (Capability, error) {
+	data, err := os.ReadFile("/proc/sys/kernel/cap_last_cap")
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, err
+	}
+	return Capability(value), nil
+}
+
+func prctl(option uintptr, arg2 uintptr, arg3 uintptr) error {
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, option, arg2, arg3, 0, 0, 0)
+	if errno != 0 {
+		return Error{Errno: errno}
+	}
+	return nil
+}
+
+// DropBoundingSet removes all the capabilities, which are not in keep, from the bounding set of the calling thread.
+func DropBoundingSet(keep CapabilitySet) error {
+	last, err := LastCapability()
+	if err != nil {
+		return err
+	}
+
+	for c := Capability(0); c <= last; c++ {
+		if keep.Has(c) {
+			continue
+		}
+		if err := prctl(PR_CAPBSET_DROP, uintptr(c), 0); err != nil {
+			return fmt.Errorf("could not drop %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+type capHeader struct {
+	Version uint32
+	Pid     int32
+}
+
+type capData struct {
+	Effective   uint32
+	Permitted   uint32
+	Inheritable uint32
+}
+
+// SetCapabilities sets the effective, permitted and inheritable sets of the calling thread.
+func SetCapabilities(effective CapabilitySet, permitted CapabilitySet, inheritable CapabilitySet) error {
+	header := capHeader{Version: _LINUX_CAPABILITY_VERSION_3}
+	data := [2]capData{
+		{Effective: uint32(effective), Permitted: uint32(permitted), Inheritable: uint32(inheritable)},
+		{Effective: uint32(effective >> 32), Permitted: uint32(permitted >> 32), Inheritable: uint32(inheritable >> 32)},
+	}
+
+	_, _, errno := syscall.RawSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0)
+	if errno != 0 {
+		return Error{Errno: errno}
+	}
+	return nil
+}
+
+// SetAmbientCapabilities replaces the ambient set of the calling thread. The capabilities
+// have to be both in the permitted and inheritable sets.
+func SetAmbientCapabilities(ambient CapabilitySet) error {
+	if err := prctl(PR_CAP_AMBIENT, PR_CAP_AMBIENT_CLEAR_ALL, 0); err != nil {
+		return fmt.Errorf("could not clear ambient set: %w", err)
+	}
+
+	for i := range capabilityNames {
+		c := Capability(i)
+		if !ambient.Has(c) {
+			continue
+		}
+		if err := prctl(PR_CAP_AMBIENT, PR_CAP_AMBIENT_RAISE, uintptr(c)); err != nil {
+			return fmt.Errorf("could not raise ambient %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// SetNoNewPrivs prevents the calling thread and its children from gaining privileges through execve.
+func SetNoNewPrivs() error {
+	return prctl(PR_SET_NO_NEW_PRIVS, 1, 0)
+}
+
+// SetKeepCapabilities keeps the permitted set of the calling thread, once it switches from root to another user.
+func SetKeepCapabilities() error {
+	return prctl(PR_SET_KEEPCAPS, 1, 0)
+}
+
+// RestrictRoot makes execve treat root as any other user, so it keeps only its ambient capabilities,
+// instead of being granted the whole bounding set. The setting is locked, it is inherited by all the children.
+func RestrictRoot() error {
+	return prctl(PR_SET_SECUREBITS, SECBIT_NOROOT|SECBIT_NOROOT_LOCKED, 0)
+}
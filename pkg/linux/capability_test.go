@@ -0,0 +1,165 @@
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const capabilityHelperEnv = "FRAGMA_CAPABILITY_HELPER"
+
+var (
+	testBounding = NewCapabilitySet(CAP_CHOWN, CAP_KILL, CAP_NET_BIND_SERVICE, CAP_NET_RAW)
+	testCaps     = NewCapabilitySet(CAP_CHOWN, CAP_NET_BIND_SERVICE)
+	testAmbient  = NewCapabilitySet(CAP_NET_BIND_SERVICE)
+)
+
+func init() {
+	// /proc/<pid>/status shows the main thread, so the helpers must stay on it.
+	if len(os.Getenv(capabilityHelperEnv)) != 0 {
+		runtime.LockOSThread()
+	}
+}
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(capabilityHelperEnv) {
+	case "1":
+		capabilityHelper()
+		return
+	case "exec":
+		restrictedRootHelper()
+		return
+	case "status":
+		caps, err := ReadCapabilities(os.Getpid())
+		if err != nil {
+			fmt.Printf("error: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%x %x\n", uint64(caps.Effective), uint64(caps.Permitted))
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func capabilityHelper() {
+	if err := DropBoundingSet(testBounding); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := SetCapabilities(testCaps, testCaps, testAmbient); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := SetAmbientCapabilities(testAmbient); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := SetNoNewPrivs(); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("ready")
+
+	// Waits until the test closes stdin.
+	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+}
+
+// restrictedRootHelper restricts the capabilities of root and executes the status helper.
+func restrictedRootHelper() {
+	if err := DropBoundingSet(testBounding); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := RestrictRoot(); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := SetCapabilities(testCaps, testCaps, testAmbient); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := SetAmbientCapabilities(testAmbient); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+
+	// The executed helper reports its own capabilities.
+	if err := os.Setenv(capabilityHelperEnv, "status"); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := syscall.Exec("/proc/self/exe", []string{os.Args[0]}, os.Environ()); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func TestCapabilitySet_Names(t *testing.T) {
+	set, err := ParseCapabilitySet([]string{"chown", "CAP_NET_RAW", "sys_admin"})
+	require.NoError(t, err)
+	require.Equal(t, NewCapabilitySet(CAP_CHOWN, CAP_NET_RAW, CAP_SYS_ADMIN), set)
+	require.Equal(t, []string{"CAP_CHOWN", "CAP_NET_RAW", "CAP_SYS_ADMIN"}, set.Names())
+
+	_, err = ParseCapabilitySet([]string{"not_a_capability"})
+	require.Error(t, err)
+}
+
+func TestSetCapabilities(t *testing.T) {
+	current, err := ReadCapabilities(os.Getpid())
+	require.NoError(t, err)
+	if !current.Effective.Has(CAP_SETPCAP) {
+		t.Skip("CAP_SETPCAP is required")
+	}
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), capabilityHelperEnv+"=1")
+	stdin, err := cmd.StdinPipe()
+	require.NoError(t, err)
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	defer func() {
+		_ = stdin.Close()
+		_ = cmd.Wait()
+	}()
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "ready\n", line)
+
+	caps, err := ReadCapabilities(cmd.Process.Pid)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{
+		Inheritable: testAmbient,
+		Permitted:   testCaps,
+		Effective:   testCaps,
+		Bounding:    testBounding,
+		Ambient:     testAmbient,
+	}, caps)
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", cmd.Process.Pid))
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(status), "NoNewPrivs:\t1"))
+}
+
+func TestRestrictRoot(t *testing.T) {
+	current, err := ReadCapabilities(os.Getpid())
+	require.NoError(t, err)
+	if os.Getuid() != 0 || !current.Effective.Has(CAP_SETPCAP) {
+		t.Skip("root with CAP_SETPCAP is required")
+	}
+
+	// Without the restriction execve would grant the whole bounding set to root.
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), capabilityHelperEnv+"=exec")
+	output, err := cmd.Output()
+	require.NoError(t, err, string(output))
+	require.Equal(t, fmt.Sprintf("%x %x\n", uint64(testAmbient), uint64(testAmbient)), string(output))
+}
@@ -0,0 +1,25 @@
+package linux
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	PR_SET_PDEATHSIG = 1
+	PR_GET_PDEATHSIG = 2
+)
+
+// ParentDeathSignal returns the signal the calling thread receives once its parent exits, zero if there is none.
+func ParentDeathSignal() (syscall.Signal, error) {
+	var signal int32
+	if err := prctl(PR_GET_PDEATHSIG, uintptr(unsafe.Pointer(&signal)), 0); err != nil {
+		return 0, err
+	}
+	return syscall.Signal(signal), nil
+}
+
+// SetParentDeathSignal makes the calling thread receive the signal once its parent exits.
+func SetParentDeathSignal(signal syscall.Signal) error {
+	return prctl(PR_SET_PDEATHSIG, uintptr(signal), 0)
+}
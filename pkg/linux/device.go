@@ -1,36 +1,40 @@
 package linux
 
 import (
-	"os"
+	"fmt"
 	"syscall"
 )
 
-func SetupDeviceSymlinks() error {
-	if err := CreateSymlink("/dev/pts/ptmx", "/dev/ptmx"); err != nil {
-		return err
-	}
-	if err := CreateSymlink("/proc/self/fd/0", "/dev/stdin"); err != nil {
-		return err
-	}
-	if err := CreateSymlink("/proc/self/fd/1", "/dev/stdout"); err != nil {
-		return err
-	}
-	if err := CreateSymlink("/proc/self/fd/2", "/dev/stderr"); err != nil {
-		return err
-	}
-
-	return nil
+// deviceSymlinks are created in the /dev of a container, the targets are resolved inside the container.
+var deviceSymlinks = []struct {
+	Target string
+	Path   string
+}{
+	{Target: "/dev/pts/ptmx", Path: "/dev/ptmx"},
+	{Target: "/proc/self/fd", Path: "/dev/fd"},
+	{Target: "/proc/self/fd/0", Path: "/dev/stdin"},
+	{Target: "/proc/self/fd/1", Path: "/dev/stdout"},
+	{Target: "/proc/self/fd/2", Path: "/dev/stderr"},
 }
 
-func CreateSymlink(source string, target string) error {
-	if _, err := os.Stat(target); err == nil {
-		// TODO: maybe check if the device is ok and leave it
-		if err := os.Remove(target); err != nil {
-			return err
+// SetupDeviceSymlinks creates the standard symlinks in the /dev of root, which is expected to be a fresh tmpfs.
+func SetupDeviceSymlinks(root string) error {
+	for _, link := range deviceSymlinks {
+		path, err := ResolveInRoot(root, link.Path)
+		if err != nil {
+			return fmt.Errorf("could not resolve %s: %w", link.Path, err)
+		}
+		if err := syscall.Symlink(link.Target, path); err != nil {
+			return fmt.Errorf("could not create %s: %w", link.Path, err)
 		}
-	}
-	if err := syscall.Symlink(source, target); err != nil {
-		return err
 	}
 	return nil
 }
+
+func Major(dev uint64) uint32 {
+	return uint32(((dev >> 8) & 0xfff) | ((dev >> 32) &^ 0xfff))
+}
+
+func Minor(dev uint64) uint32 {
+	return uint32((dev & 0xff) | ((dev >> 12) &^ 0xff))
+}
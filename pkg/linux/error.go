@@ -12,3 +12,7 @@ type Error struct {
 func (e Error) Error() string {
 	return fmt.Sprintf("linux syscall error: %s", e.Errno.Error())
 }
+
+func (e Error) Unwrap() error {
+	return e.Errno
+}
@@ -0,0 +1,99 @@
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+var ErrUnknownFilesystem = errors.New("unknown filesystem")
+
+const (
+	FilesystemExt4     = "ext4"
+	FilesystemSquashfs = "squashfs"
+	FilesystemErofs    = "erofs"
+	FilesystemXfs      = "xfs"
+	FilesystemBtrfs    = "btrfs"
+)
+
+// superblock identifies a filesystem by the magic at the given offset of the device.
+type superblock struct {
+	fsType string
+	offset int64
+	magic  []byte
+	// readOnly filesystems cannot be mounted for writing.
+	readOnly bool
+	// valid checks the rest of the superblock, if the magic is not distinctive enough.
+	valid func(reader io.ReaderAt) bool
+}
+
+// The magic numbers are stored in the byte order of the filesystem. The longer magics are checked first,
+// two bytes of another filesystem can match the short magic of ext4 by chance.
+var superblocks = []superblock{
+	{fsType: FilesystemSquashfs, offset: 0, magic: []byte("hsqs"), readOnly: true},
+	{fsType: FilesystemErofs, offset: 1024, magic: []byte{0xe2, 0xe1, 0xf5, 0xe0}, readOnly: true},
+	{fsType: FilesystemXfs, offset: 0, magic: []byte("XFSB")},
+	{fsType: FilesystemBtrfs, offset: 0x10000 + 0x40, magic: []byte("_BHRfS_M")},
+	// The ext4 driver mounts ext2 and ext3 as well.
+	{fsType: FilesystemExt4, offset: 1024 + 0x38, magic: []byte{0x53, 0xef}, valid: validExt4},
+}
+
+// maxExt4LogBlockSize is the largest s_log_block_size, the block size is 1024 << s_log_block_size up to 64KiB.
+const maxExt4LogBlockSize = 6
+
+func validExt4(reader io.ReaderAt) bool {
+	logBlockSize := make([]byte, 4)
+	if _, err := reader.ReadAt(logBlockSize, 1024+0x18); err != nil {
+		return false
+	}
+	return binary.LittleEndian.Uint32(logBlockSize) <= maxExt4LogBlockSize
+}
+
+// SupportedFilesystem reports whether the filesystem can be mounted by MountDevice.
+func SupportedFilesystem(fsType string) bool {
+	for _, sb := range superblocks {
+		if sb.fsType == fsType {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadOnlyFilesystem reports whether the filesystem can only be mounted read-only.
+func ReadOnlyFilesystem(fsType string) bool {
+	for _, sb := range superblocks {
+		if sb.fsType == fsType {
+			return sb.readOnly
+		}
+	}
+	return false
+}
+
+// DetectFilesystem reads the superblock of the device or the image file and returns the type of its filesystem.
+func DetectFilesystem(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return detectFilesystem(file)
+}
+
+func detectFilesystem(reader io.ReaderAt) (string, error) {
+	for _, sb := range superblocks {
+		magic := make([]byte, len(sb.magic))
+		if _, err := reader.ReadAt(magic, sb.offset); err != nil {
+			// The image is smaller than the superblock of the filesystem.
+			if errors.Is(err, io.EOF) {
+				continue
+			}
+			return "", err
+		}
+		if bytes.Equal(magic, sb.magic) && (sb.valid == nil || sb.valid(reader)) {
+			return sb.fsType, nil
+		}
+	}
+	return "", ErrUnknownFilesystem
+}
@@ -0,0 +1,56 @@
+package linux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeImage(t *testing.T, offset int64, magic []byte) string {
+	path := filepath.Join(t.TempDir(), "image")
+	image := make([]byte, 0x20000)
+	copy(image[offset:], magic)
+	require.NoError(t, os.WriteFile(path, image, 0644))
+	return path
+}
+
+func TestDetectFilesystem(t *testing.T) {
+	for _, sb := range superblocks {
+		fsType, err := DetectFilesystem(writeImage(t, sb.offset, sb.magic))
+		require.NoError(t, err)
+		require.Equal(t, sb.fsType, fsType)
+	}
+
+	_, err := DetectFilesystem(writeImage(t, 0, []byte("unknown")))
+	require.True(t, errors.Is(err, ErrUnknownFilesystem))
+
+	// The bytes of another filesystem, which happen to match the magic of ext4, are not mistaken for it.
+	path := writeImage(t, 0, []byte("XFSB"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	copy(data[1024+0x38:], []byte{0x53, 0xef})
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	fsType, err := DetectFilesystem(path)
+	require.NoError(t, err)
+	require.Equal(t, FilesystemXfs, fsType)
+
+	// An ext4 superblock with an impossible block size is rejected.
+	path = writeImage(t, 1024+0x38, []byte{0x53, 0xef})
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	data[1024+0x18] = maxExt4LogBlockSize + 1
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	_, err = DetectFilesystem(path)
+	require.True(t, errors.Is(err, ErrUnknownFilesystem))
+
+	// The images smaller than some of the superblocks are still detected.
+	small := filepath.Join(t.TempDir(), "small")
+	require.NoError(t, os.WriteFile(small, []byte("hsqs"), 0644))
+	fsType, err = DetectFilesystem(small)
+	require.NoError(t, err)
+	require.Equal(t, FilesystemSquashfs, fsType)
+	require.True(t, ReadOnlyFilesystem(fsType))
+}
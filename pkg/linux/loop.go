@@ -8,7 +8,13 @@ typedef struct loop_config loop_config_t;
 */
 import "C"
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 	"unsafe"
 )
@@ -18,9 +24,25 @@ const (
 	LOOP_CTL_GET_FREE = 0x4C82
 	LOOP_SET_FD       = 0x4C00
 	LOOP_SET_STATUS64 = 0x4C04
+	LOOP_GET_STATUS64 = 0x4C05
 	LOOP_CLR_FD       = 0x4C01
+
+	LOOP_SET_DIRECT_IO  = 0x4C08
+	LOOP_SET_BLOCK_SIZE = 0x4C09
 )
 
+const (
+	LO_FLAGS_READ_ONLY = 1
+	LO_FLAGS_AUTOCLEAR = 4
+	LO_FLAGS_PARTSCAN  = 8
+	LO_FLAGS_DIRECT_IO = 16
+)
+
+const LO_NAME_SIZE = 64
+
+// loopAttachRetries limits the attempts to attach a file, when other callers take the free devices first.
+const loopAttachRetries = 16
+
 type LoopInfo struct {
 	Device         uint64 /* ioctl r/o */
 	Inode          uint64 /* ioctl r/o */
@@ -60,6 +82,36 @@ func (l LoopInfo) ToCStruct() C.loop_info64_t {
 	return result
 }
 
+func loopInfoFromCStruct(info C.loop_info64_t) LoopInfo {
+	result := LoopInfo{
+		Device:         uint64(info.lo_device),
+		Inode:          uint64(info.lo_inode),
+		RDevice:        uint64(info.lo_rdevice),
+		Offset:         uint64(info.lo_offset),
+		SizeLimit:      uint64(info.lo_sizelimit),
+		Number:         uint32(info.lo_number),
+		EncryptType:    uint32(info.lo_encrypt_type),
+		EncryptKeySize: uint32(info.lo_encrypt_key_size),
+		Flags:          uint32(info.lo_flags),
+	}
+	for i := 0; i < 64; i++ {
+		result.FileName[i] = byte(info.lo_file_name[i])
+		result.CryptName[i] = byte(info.lo_crypt_name[i])
+	}
+	result.Init[0] = uint64(info.lo_init[0])
+	result.Init[1] = uint64(info.lo_init[1])
+	return result
+}
+
+// BackingFile returns the name of the backing file, the kernel truncates it to LO_NAME_SIZE-1 bytes.
+func (l LoopInfo) BackingFile() string {
+	name := l.FileName[:]
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	return string(name)
+}
+
 type LoopConfig struct {
 	Fd        uint32
 	BlockSize uint32
@@ -109,29 +161,229 @@ func LoopConfigure(deviceFd int, cfg LoopConfig) error {
 	return nil
 }
 
-func LoopSetupDevice(file string) (string, error) {
-	fileFd, err := syscall.Open(file, syscall.O_RDWR, 0644)
+// LoopOptions configure a loop device attached by LoopAttach.
+type LoopOptions struct {
+	ReadOnly bool
+	// AutoClear detaches the device once it is no longer used, after the last unmount or close.
+	AutoClear bool
+	// DirectIO bypasses the page cache of the backing file.
+	DirectIO bool
+	// PartScan makes the kernel scan the device for partitions.
+	PartScan bool
+	// Offset is the position of the device data in the backing file.
+	Offset uint64
+	// SizeLimit is the size of the device in bytes, 0 means until the end of the backing file.
+	SizeLimit uint64
+	// BlockSize is the logical block size, the default is used if 0.
+	BlockSize uint32
+}
+
+func (o LoopOptions) flags() uint32 {
+	var flags uint32
+	if o.ReadOnly {
+		flags |= LO_FLAGS_READ_ONLY
+	}
+	if o.AutoClear {
+		flags |= LO_FLAGS_AUTOCLEAR
+	}
+	if o.DirectIO {
+		flags |= LO_FLAGS_DIRECT_IO
+	}
+	if o.PartScan {
+		flags |= LO_FLAGS_PARTSCAN
+	}
+	return flags
+}
+
+// LoopAttach attaches the file to a free loop device and returns the path of the device.
+func LoopAttach(file string, options LoopOptions) (string, error) {
+	// The loop device inherits the access mode of the backing file descriptor.
+	mode := syscall.O_RDWR
+	if options.ReadOnly {
+		mode = syscall.O_RDONLY
+	}
+
+	fileFd, err := syscall.Open(file, mode|syscall.O_CLOEXEC, 0644)
 	if err != nil {
 		return "", err
 	}
 	defer syscall.Close(fileFd)
 
-	devicePath, err := LoopGetFreeDevice()
+	cfg := LoopConfig{
+		Fd:        uint32(fileFd),
+		BlockSize: options.BlockSize,
+		Info: LoopInfo{
+			Offset:    options.Offset,
+			SizeLimit: options.SizeLimit,
+			Flags:     options.flags(),
+		},
+	}
+	name := file
+	if absolute, err := filepath.Abs(file); err == nil {
+		name = absolute
+	}
+	copy(cfg.Info.FileName[:LO_NAME_SIZE-1], name)
+
+	// Another caller can take the device between LOOP_CTL_GET_FREE and LOOP_CONFIGURE, the device is busy then.
+	for attempt := 0; ; attempt++ {
+		devicePath, err := LoopGetFreeDevice()
+		if err != nil {
+			return "", err
+		}
+
+		err = loopConfigureDevice(devicePath, cfg)
+		if err == nil {
+			return devicePath, nil
+		}
+		if !errors.Is(err, syscall.EBUSY) || attempt+1 >= loopAttachRetries {
+			return "", err
+		}
+	}
+}
+
+func loopConfigureDevice(devicePath string, cfg LoopConfig) error {
+	deviceFd, err := syscall.Open(devicePath, syscall.O_RDWR|syscall.O_CLOEXEC, 0644)
 	if err != nil {
-		return "", err
+		return err
 	}
+	defer syscall.Close(deviceFd)
 
-	deviceFd, err := syscall.Open(devicePath, syscall.O_RDWR, 0644)
+	err = LoopConfigure(deviceFd, cfg)
+	// LOOP_CONFIGURE is available since Linux 5.8, the older kernels configure the device in two steps.
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return loopSetFdAndStatus(deviceFd, cfg)
+	}
+	return err
+}
+
+// loopSetFdAndStatus configures the device with the legacy ioctls, the device is detached if any of them fails.
+func loopSetFdAndStatus(deviceFd int, cfg LoopConfig) error {
+	if err := LoopSetFd(deviceFd, int(cfg.Fd)); err != nil {
+		return err
+	}
+
+	if err := loopSetup(deviceFd, cfg); err != nil {
+		_, _, _ = syscall.RawSyscall(syscall.SYS_IOCTL, uintptr(deviceFd), LOOP_CLR_FD, 0)
+		return err
+	}
+	return nil
+}
+
+func loopSetup(deviceFd int, cfg LoopConfig) error {
+	// The read-only flag follows the access mode of the file and the direct IO has its own ioctl.
+	info := cfg.Info
+	info.Flags &= LO_FLAGS_AUTOCLEAR | LO_FLAGS_PARTSCAN
+	if err := LoopSetStatus(deviceFd, info); err != nil {
+		return err
+	}
+
+	if cfg.BlockSize != 0 {
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_IOCTL, uintptr(deviceFd), LOOP_SET_BLOCK_SIZE, uintptr(cfg.BlockSize)); errno != 0 {
+			return Error{Errno: errno}
+		}
+	}
+	if cfg.Info.Flags&LO_FLAGS_DIRECT_IO != 0 {
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_IOCTL, uintptr(deviceFd), LOOP_SET_DIRECT_IO, 1); errno != 0 {
+			return Error{Errno: errno}
+		}
+	}
+	return nil
+}
+
+func LoopSetStatus(deviceFd int, info LoopInfo) error {
+	cinfo := info.ToCStruct()
+	_, _, errno := syscall.RawSyscall(syscall.SYS_IOCTL, uintptr(deviceFd), LOOP_SET_STATUS64, uintptr(unsafe.Pointer(&cinfo)))
+	if errno != 0 {
+		return Error{Errno: errno}
+	}
+	return nil
+}
+
+func LoopGetStatus(deviceFd int) (LoopInfo, error) {
+	cinfo := C.loop_info64_t{}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_IOCTL, uintptr(deviceFd), LOOP_GET_STATUS64, uintptr(unsafe.Pointer(&cinfo)))
+	if errno != 0 {
+		return LoopInfo{}, Error{Errno: errno}
+	}
+	return loopInfoFromCStruct(cinfo), nil
+}
+
+// LoopStatus returns the configuration of the device, it fails with ENXIO if no file is attached.
+func LoopStatus(device string) (LoopInfo, error) {
+	deviceFd, err := syscall.Open(device, syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
 	if err != nil {
-		return "", err
+		return LoopInfo{}, err
 	}
 	defer syscall.Close(deviceFd)
 
-	if err := LoopSetFd(deviceFd, fileFd); err != nil {
-		return "", err
+	return LoopGetStatus(deviceFd)
+}
+
+// LoopSetupDevice attaches the file to a free loop device with the default options.
+func LoopSetupDevice(file string, readOnly bool) (string, error) {
+	return LoopAttach(file, LoopOptions{ReadOnly: readOnly})
+}
+
+// LoopDevice is a loop device with an attached file.
+type LoopDevice struct {
+	Path  string
+	Major uint32
+	Minor uint32
+	// BackingFile is the full path of the attached file, unlike the name in Info.
+	BackingFile string
+	Info        LoopInfo
+}
+
+const sysBlockPath = "/sys/block"
+
+// LoopList returns the loop devices with an attached file, ordered by their number.
+func LoopList() ([]LoopDevice, error) {
+	entries, err := os.ReadDir(sysBlockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []LoopDevice
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "loop") {
+			continue
+		}
+		// The backing_file attribute exists only while a file is attached.
+		backingFile, err := os.ReadFile(filepath.Join(sysBlockPath, entry.Name(), "loop", "backing_file"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join("/dev", entry.Name())
+		var stat syscall.Stat_t
+		if err := syscall.Stat(path, &stat); err != nil {
+			return nil, err
+		}
+		info, err := LoopStatus(path)
+		// The device was detached in the meantime.
+		if errors.Is(err, syscall.ENXIO) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		devices = append(devices, LoopDevice{
+			Path:        path,
+			Major:       Major(stat.Rdev),
+			Minor:       Minor(stat.Rdev),
+			BackingFile: strings.TrimSuffix(string(backingFile), "\n"),
+			Info:        info,
+		})
 	}
 
-	return devicePath, nil
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Info.Number < devices[j].Info.Number
+	})
+	return devices, nil
 }
 
 func LoopClear(device string) error {
@@ -0,0 +1,78 @@
+package linux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoopAttach(t *testing.T) {
+	if syscall.Access("/dev/loop-control", syscall.O_RDWR) != nil {
+		t.Skip("loop devices are not available")
+	}
+
+	file := filepath.Join(t.TempDir(), "image")
+	require.NoError(t, os.WriteFile(file, make([]byte, 1<<20), 0644))
+
+	device, err := LoopAttach(file, LoopOptions{ReadOnly: true, Offset: 4096, SizeLimit: 64 << 10, BlockSize: 4096})
+	require.NoError(t, err)
+	defer LoopClear(device)
+
+	info, err := LoopStatus(device)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4096), info.Offset)
+	require.Equal(t, uint64(64<<10), info.SizeLimit)
+	require.NotZero(t, info.Flags&LO_FLAGS_READ_ONLY)
+	require.Equal(t, file, info.BackingFile())
+
+	devices, err := LoopList()
+	require.NoError(t, err)
+	var found bool
+	for _, attached := range devices {
+		if attached.Path == device {
+			found = true
+			require.Equal(t, file, attached.BackingFile)
+		}
+	}
+	require.True(t, found)
+
+	require.NoError(t, LoopClear(device))
+	_, err = LoopStatus(device)
+	require.True(t, errors.Is(err, syscall.ENXIO))
+}
+
+func TestLoopAttach_Concurrent(t *testing.T) {
+	if syscall.Access("/dev/loop-control", syscall.O_RDWR) != nil {
+		t.Skip("loop devices are not available")
+	}
+
+	file := filepath.Join(t.TempDir(), "image")
+	require.NoError(t, os.WriteFile(file, make([]byte, 1<<20), 0644))
+
+	// The callers race for the same free devices.
+	const count = 8
+	type result struct {
+		device string
+		err    error
+	}
+	results := make(chan result, count)
+	for i := 0; i < count; i++ {
+		go func() {
+			device, err := LoopAttach(file, LoopOptions{ReadOnly: true})
+			results <- result{device: device, err: err}
+		}()
+	}
+
+	attached := map[string]bool{}
+	for i := 0; i < count; i++ {
+		r := <-results
+		require.NoError(t, r.err)
+		defer LoopClear(r.device)
+		attached[r.device] = true
+	}
+	require.Len(t, attached, count)
+}
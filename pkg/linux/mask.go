@@ -0,0 +1,55 @@
+package linux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockedFlags have to be kept by a remount, a user namespace cannot clear them on the mounts it inherited.
+const lockedFlags = syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC
+
+// MaskPath hides the path, a directory is covered with an empty read-only tmpfs and a file with /dev/null.
+// A missing path is ignored, the kernel does not necessarily provide all the masked files.
+func MaskPath(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if err := syscall.Mount("tmpfs", path, "tmpfs", syscall.MS_RDONLY, "size=0"); err != nil {
+			return fmt.Errorf("could not mount tmpfs: %w", err)
+		}
+		return nil
+	}
+	if err := syscall.Mount("/dev/null", path, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("could not bind mount /dev/null: %w", err)
+	}
+	return nil
+}
+
+// ReadonlyPath remounts the path read-only, a missing path is ignored.
+func ReadonlyPath(path string) error {
+	if err := syscall.Mount(path, path, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		if errors.Is(err, syscall.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("could not bind mount: %w", err)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return err
+	}
+	// The statfs flags match the mount flags.
+	flags := uintptr(syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY) | uintptr(stat.Flags)&lockedFlags
+	if err := syscall.Mount("", path, "", flags, ""); err != nil {
+		return fmt.Errorf("could not remount read-only: %w", err)
+	}
+	return nil
+}
@@ -1,20 +1,24 @@
 package linux
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/mmbednarek/fragma/pkg/util"
 )
 
+const MountRoot = "/opt/frama/mount/"
+
 type Mount struct {
 	Name string
 	Path string
 }
 
-func MountDevice(path string) (Mount, error) {
+func newMountPoint() (Mount, error) {
 	name := util.String(6)
-	mountPath := "/opt/frama/mount/" + name + "/"
+	mountPath := MountRoot + name + "/"
 
 	if err := os.Mkdir(mountPath, 0755); err != nil {
 		if !os.IsExist(err) {
@@ -22,12 +26,95 @@ func MountDevice(path string) (Mount, error) {
 		}
 	}
 
-	err := syscall.Mount(path, mountPath, "ext4", 0, "")
+	return Mount{Name: name, Path: mountPath}, nil
+}
+
+// MS_LAZYTIME is missing in the syscall package.
+const MS_LAZYTIME = 1 << 25
+
+// mountFlags are the mount options handled by the kernel for all the filesystems, the rest is passed to the filesystem.
+var mountFlags = map[string]uintptr{
+	"ro":          syscall.MS_RDONLY,
+	"nosuid":      syscall.MS_NOSUID,
+	"nodev":       syscall.MS_NODEV,
+	"noexec":      syscall.MS_NOEXEC,
+	"sync":        syscall.MS_SYNCHRONOUS,
+	"dirsync":     syscall.MS_DIRSYNC,
+	"noatime":     syscall.MS_NOATIME,
+	"nodiratime":  syscall.MS_NODIRATIME,
+	"relatime":    syscall.MS_RELATIME,
+	"strictatime": syscall.MS_STRICTATIME,
+	"lazytime":    MS_LAZYTIME,
+}
+
+// parseMountOptions splits the comma separated options into the mount flags and the options of the filesystem.
+func parseMountOptions(options string) (uintptr, string) {
+	var flags uintptr
+	var data []string
+	for _, option := range strings.Split(options, ",") {
+		if len(option) == 0 {
+			continue
+		}
+		if flag, ok := mountFlags[option]; ok {
+			flags |= flag
+			continue
+		}
+		data = append(data, option)
+	}
+	return flags, strings.Join(data, ",")
+}
+
+// DeviceOptions describe how a block device is mounted.
+type DeviceOptions struct {
+	// FsType is detected from the superblock of the device if empty.
+	FsType   string
+	ReadOnly bool
+	// Data are the mount options separated by commas, the generic ones such as noatime are turned into flags.
+	Data string
+}
+
+// MountDevice mounts the block device, the filesystems supporting only reads are always mounted read-only.
+func MountDevice(path string, options DeviceOptions) (Mount, error) {
+	fsType := options.FsType
+	if len(fsType) == 0 {
+		detected, err := DetectFilesystem(path)
+		if err != nil {
+			return Mount{}, err
+		}
+		fsType = detected
+	}
+
+	flags, data := parseMountOptions(options.Data)
+	if options.ReadOnly || ReadOnlyFilesystem(fsType) {
+		flags |= syscall.MS_RDONLY
+	}
+
+	mount, err := newMountPoint()
 	if err != nil {
 		return Mount{}, err
 	}
 
-	return Mount{Name: name, Path: mountPath}, nil
+	if err := syscall.Mount(path, mount.Path, fsType, flags, data); err != nil {
+		return Mount{}, err
+	}
+
+	return mount, nil
+}
+
+// MountOverlay mounts a copy-on-write overlay of lower, the changes are written to upper.
+// The work directory must be empty and reside on the same filesystem as upper.
+func MountOverlay(lower string, upper string, work string) (Mount, error) {
+	mount, err := newMountPoint()
+	if err != nil {
+		return Mount{}, err
+	}
+
+	options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", lower, upper, work)
+	if err := syscall.Mount("overlay", mount.Path, "overlay", 0, options); err != nil {
+		return Mount{}, err
+	}
+
+	return mount, nil
 }
 
 func (m *Mount) Unmount() error {
@@ -36,3 +123,44 @@ func (m *Mount) Unmount() error {
 	}
 	return nil
 }
+
+// SetRootPropagation changes the propagation type of all the mounts in the current mount namespace.
+// It is used to make sure mounts made inside a container never propagate back to the host.
+func SetRootPropagation(propagation uintptr) error {
+	if err := syscall.Mount("", "/", "", propagation|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("could not change root propagation: %w", err)
+	}
+	return nil
+}
+
+// PivotRoot makes newRoot the root mount of the current mount namespace and detaches the old root.
+func PivotRoot(newRoot string) error {
+	// pivot_root requires the new root to be a mount point.
+	if err := syscall.Mount(newRoot, newRoot, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("could not bind mount new root: %w", err)
+	}
+
+	if err := syscall.Chdir(newRoot); err != nil {
+		return fmt.Errorf("could not change dir to new root: %w", err)
+	}
+
+	// Stacks the old root on top of the new one, this way no put_old directory is needed.
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("could not pivot root: %w", err)
+	}
+
+	// Prevents the unmount of the old root from propagating to the host.
+	if err := syscall.Mount("", ".", "", syscall.MS_SLAVE|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("could not make old root slave: %w", err)
+	}
+
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("could not detach old root: %w", err)
+	}
+
+	if err := syscall.Chdir("/"); err != nil {
+		return fmt.Errorf("could not change dir to root: %w", err)
+	}
+
+	return nil
+}
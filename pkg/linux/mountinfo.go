@@ -0,0 +1,128 @@
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MountInfo is a line of /proc/<pid>/mountinfo.
+type MountInfo struct {
+	ID       int
+	ParentID int
+	Major    uint32
+	Minor    uint32
+	// Root is the path of the mounted directory within the filesystem.
+	Root         string
+	MountPoint   string
+	Options      string
+	FsType       string
+	Source       string
+	SuperOptions string
+}
+
+// ReadMountInfo reads the mounts of the mount namespace of the process, the current one if pid is 0.
+func ReadMountInfo(pid int) ([]MountInfo, error) {
+	path := "/proc/self/mountinfo"
+	if pid != 0 {
+		path = fmt.Sprintf("/proc/%d/mountinfo", pid)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseMountInfo(file)
+}
+
+// ParseMountInfo parses the mountinfo format, the mounts are in the order they were mounted.
+func ParseMountInfo(reader io.Reader) ([]MountInfo, error) {
+	var mounts []MountInfo
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		mount, err := parseMountInfoLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, mount)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// parseMountInfoLine parses a line in the format:
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func parseMountInfoLine(line string) (MountInfo, error) {
+	fields := strings.Fields(line)
+	// The optional fields are terminated by a single hyphen.
+	separator := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 || len(fields) < separator+4 {
+		return MountInfo{}, fmt.Errorf("invalid mountinfo line: %q", line)
+	}
+
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return MountInfo{}, fmt.Errorf("invalid mount id: %w", err)
+	}
+	parentID, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return MountInfo{}, fmt.Errorf("invalid parent id: %w", err)
+	}
+	major, minor, ok := strings.Cut(fields[2], ":")
+	if !ok {
+		return MountInfo{}, fmt.Errorf("invalid device: %s", fields[2])
+	}
+	majorNum, err := strconv.ParseUint(major, 10, 32)
+	if err != nil {
+		return MountInfo{}, fmt.Errorf("invalid device: %w", err)
+	}
+	minorNum, err := strconv.ParseUint(minor, 10, 32)
+	if err != nil {
+		return MountInfo{}, fmt.Errorf("invalid device: %w", err)
+	}
+
+	return MountInfo{
+		ID:           id,
+		ParentID:     parentID,
+		Major:        uint32(majorNum),
+		Minor:        uint32(minorNum),
+		Root:         unescapeMountPath(fields[3]),
+		MountPoint:   unescapeMountPath(fields[4]),
+		Options:      fields[5],
+		FsType:       fields[separator+1],
+		Source:       unescapeMountPath(fields[separator+2]),
+		SuperOptions: fields[separator+3],
+	}, nil
+}
+
+// unescapeMountPath decodes the octal escapes of the space, tab, newline and backslash characters.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 <= len(path) {
+			if value, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(path[i])
+	}
+	return builder.String()
+}
@@ -0,0 +1,34 @@
+package linux
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMountInfo(t *testing.T) {
+	mounts, err := ParseMountInfo(strings.NewReader(`22 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw
+43 22 7:0 / /opt/frama/mount/abc rw,relatime - ext4 /dev/loop0 rw
+46 22 0:39 / /mnt/with\040space rw,relatime shared:5 master:2 - overlay overlay rw,lowerdir=/opt/frama/mount/abc/
+`))
+	require.NoError(t, err)
+	require.Len(t, mounts, 3)
+	require.Equal(t, MountInfo{
+		ID:           43,
+		ParentID:     22,
+		Major:        7,
+		Minor:        0,
+		Root:         "/",
+		MountPoint:   "/opt/frama/mount/abc",
+		Options:      "rw,relatime",
+		FsType:       "ext4",
+		Source:       "/dev/loop0",
+		SuperOptions: "rw",
+	}, mounts[1])
+	require.Equal(t, "/mnt/with space", mounts[2].MountPoint)
+	require.Equal(t, "overlay", mounts[2].FsType)
+
+	_, err = ParseMountInfo(strings.NewReader("43 22 7:0 / /mnt rw\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,230 @@
+package linux
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	IFLA_INFO_KIND = 1
+	IFLA_INFO_DATA = 2
+	VETH_INFO_PEER = 1
+)
+
+var ErrNotIPv4 = errors.New("not an ipv4 address")
+
+// attribute is a netlink route attribute, it either holds data or nested attributes.
+type attribute struct {
+	kind     uint16
+	data     []byte
+	children []attribute
+}
+
+func stringAttribute(kind uint16, value string) attribute {
+	return attribute{kind: kind, data: append([]byte(value), 0)}
+}
+
+func uint32Attribute(kind uint16, value uint32) attribute {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, value)
+	return attribute{kind: kind, data: data}
+}
+
+func alignAttribute(length int) int {
+	return (length + syscall.RTA_ALIGNTO - 1) & ^(syscall.RTA_ALIGNTO - 1)
+}
+
+func (a attribute) encode() []byte {
+	payload := a.data
+	for _, child := range a.children {
+		payload = append(payload, child.encode()...)
+	}
+
+	length := syscall.SizeofRtAttr + len(payload)
+	result := make([]byte, alignAttribute(length))
+	binary.LittleEndian.PutUint16(result[0:2], uint16(length))
+	binary.LittleEndian.PutUint16(result[2:4], a.kind)
+	copy(result[syscall.SizeofRtAttr:], payload)
+	return result
+}
+
+func encodeAttributes(attrs []attribute) []byte {
+	var result []byte
+	for _, attr := range attrs {
+		result = append(result, attr.encode()...)
+	}
+	return result
+}
+
+func structBytes(ptr unsafe.Pointer, size int) []byte {
+	return append([]byte{}, unsafe.Slice((*byte)(ptr), size)...)
+}
+
+// Netlink is a NETLINK_ROUTE socket bound to the network namespace of the thread, that opened it.
+type Netlink struct {
+	fd  int
+	seq uint32
+}
+
+func OpenNetlink() (*Netlink, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("syscall.Socket: %w", err)
+	}
+
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("syscall.Bind: %w", err)
+	}
+
+	return &Netlink{fd: fd}, nil
+}
+
+func (n *Netlink) Close() error {
+	return syscall.Close(n.fd)
+}
+
+// request sends a single message and waits for the acknowledgement of the kernel.
+func (n *Netlink) request(kind uint16, flags uint16, payload []byte) error {
+	n.seq++
+	header := syscall.NlMsghdr{
+		Len:   uint32(syscall.SizeofNlMsghdr + len(payload)),
+		Type:  kind,
+		Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_ACK | flags,
+		Seq:   n.seq,
+	}
+	message := append(structBytes(unsafe.Pointer(&header), syscall.SizeofNlMsghdr), payload...)
+
+	if err := syscall.Sendto(n.fd, message, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return fmt.Errorf("syscall.Sendto: %w", err)
+	}
+
+	buffer := make([]byte, syscall.Getpagesize())
+	for {
+		size, _, err := syscall.Recvfrom(n.fd, buffer, 0)
+		if err != nil {
+			return fmt.Errorf("syscall.Recvfrom: %w", err)
+		}
+
+		messages, err := syscall.ParseNetlinkMessage(buffer[:size])
+		if err != nil {
+			return fmt.Errorf("syscall.ParseNetlinkMessage: %w", err)
+		}
+
+		for _, msg := range messages {
+			if msg.Header.Seq != n.seq || msg.Header.Type != syscall.NLMSG_ERROR {
+				continue
+			}
+			if len(msg.Data) < 4 {
+				return errors.New("truncated netlink error")
+			}
+			code := int32(binary.LittleEndian.Uint32(msg.Data[0:4]))
+			if code != 0 {
+				return Error{Errno: syscall.Errno(-code)}
+			}
+			return nil
+		}
+	}
+}
+
+func (n *Netlink) linkRequest(kind uint16, flags uint16, info syscall.IfInfomsg, attrs []attribute) error {
+	info.Family = syscall.AF_UNSPEC
+	payload := append(structBytes(unsafe.Pointer(&info), syscall.SizeofIfInfomsg), encodeAttributes(attrs)...)
+	return n.request(kind, flags, payload)
+}
+
+// CreateBridge creates a bridge device, the device is left down.
+func (n *Netlink) CreateBridge(name string) error {
+	return n.linkRequest(syscall.RTM_NEWLINK, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, syscall.IfInfomsg{}, []attribute{
+		stringAttribute(syscall.IFLA_IFNAME, name),
+		{kind: syscall.IFLA_LINKINFO, children: []attribute{
+			stringAttribute(IFLA_INFO_KIND, "bridge"),
+		}},
+	})
+}
+
+// CreateVethPair creates a pair of connected devices, the peer is created directly
+// in the network namespace of the process peerPid, so its name cannot collide with the host devices.
+func (n *Netlink) CreateVethPair(name string, peerName string, peerPid int) error {
+	peerInfo := syscall.IfInfomsg{Family: syscall.AF_UNSPEC}
+	peer := structBytes(unsafe.Pointer(&peerInfo), syscall.SizeofIfInfomsg)
+	peer = append(peer, encodeAttributes([]attribute{
+		stringAttribute(syscall.IFLA_IFNAME, peerName),
+		uint32Attribute(syscall.IFLA_NET_NS_PID, uint32(peerPid)),
+	})...)
+
+	return n.linkRequest(syscall.RTM_NEWLINK, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, syscall.IfInfomsg{}, []attribute{
+		stringAttribute(syscall.IFLA_IFNAME, name),
+		{kind: syscall.IFLA_LINKINFO, children: []attribute{
+			stringAttribute(IFLA_INFO_KIND, "veth"),
+			{kind: IFLA_INFO_DATA, children: []attribute{
+				{kind: VETH_INFO_PEER, data: peer},
+			}},
+		}},
+	})
+}
+
+func (n *Netlink) DeleteLink(index int) error {
+	return n.linkRequest(syscall.RTM_DELLINK, 0, syscall.IfInfomsg{Index: int32(index)}, nil)
+}
+
+func (n *Netlink) SetLinkUp(index int) error {
+	return n.linkRequest(syscall.RTM_NEWLINK, 0, syscall.IfInfomsg{
+		Index:  int32(index),
+		Flags:  syscall.IFF_UP,
+		Change: syscall.IFF_UP,
+	}, nil)
+}
+
+// SetLinkMaster attaches the device to a bridge.
+func (n *Netlink) SetLinkMaster(index int, masterIndex int) error {
+	return n.linkRequest(syscall.RTM_NEWLINK, 0, syscall.IfInfomsg{Index: int32(index)}, []attribute{
+		uint32Attribute(syscall.IFLA_MASTER, uint32(masterIndex)),
+	})
+}
+
+// AddAddress assigns an ipv4 address to the device, an already assigned address is not an error.
+func (n *Netlink) AddAddress(index int, address net.IPNet) error {
+	ip := address.IP.To4()
+	if ip == nil {
+		return ErrNotIPv4
+	}
+	prefixLen, _ := address.Mask.Size()
+
+	info := syscall.IfAddrmsg{
+		Family:    syscall.AF_INET,
+		Prefixlen: uint8(prefixLen),
+		Index:     uint32(index),
+	}
+	payload := append(structBytes(unsafe.Pointer(&info), syscall.SizeofIfAddrmsg), encodeAttributes([]attribute{
+		{kind: syscall.IFA_LOCAL, data: ip},
+		{kind: syscall.IFA_ADDRESS, data: ip},
+	})...)
+
+	return n.request(syscall.RTM_NEWADDR, syscall.NLM_F_CREATE|syscall.NLM_F_REPLACE, payload)
+}
+
+// AddDefaultRoute routes all the ipv4 traffic, that does not match other routes, through the gateway.
+func (n *Netlink) AddDefaultRoute(gateway net.IP) error {
+	ip := gateway.To4()
+	if ip == nil {
+		return ErrNotIPv4
+	}
+
+	info := syscall.RtMsg{
+		Family:   syscall.AF_INET,
+		Table:    syscall.RT_TABLE_MAIN,
+		Protocol: syscall.RTPROT_BOOT,
+		Scope:    syscall.RT_SCOPE_UNIVERSE,
+		Type:     syscall.RTN_UNICAST,
+	}
+	payload := append(structBytes(unsafe.Pointer(&info), syscall.SizeofRtMsg), encodeAttributes([]attribute{
+		{kind: syscall.RTA_GATEWAY, data: ip},
+	})...)
+
+	return n.request(syscall.RTM_NEWROUTE, syscall.NLM_F_CREATE|syscall.NLM_F_REPLACE, payload)
+}
@@ -1,74 +1,85 @@
 package linux
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"syscall"
 )
 
-type DeviceType int
-
-const (
-	// Basic utility character device
-	NullDevice    DeviceType = (1 << 8) | (3 & 0xff) | ((3 & 0xfff00) << 12)
-	ZeroDevice    DeviceType = (1 << 8) | (5 & 0xff) | ((5 & 0xfff00) << 12)
-	FullDevice    DeviceType = (1 << 8) | (7 & 0xff) | ((7 & 0xfff00) << 12)
-	RandomDevice  DeviceType = (1 << 8) | (8 & 0xff) | ((8 & 0xfff00) << 12)
-	URandomDevice DeviceType = (1 << 8) | (9 & 0xff) | ((9 & 0xfff00) << 12)
+// Device is a device node created inside a container.
+type Device struct {
+	// Path is an absolute path inside the container.
+	Path string
+	// Type is either syscall.S_IFCHR or syscall.S_IFBLK.
+	Type  uint32
+	Major uint32
+	Minor uint32
+	Mode  uint32
+	Uid   uint32
+	Gid   uint32
+}
 
-	// Console character devices
-	TTYDevice     DeviceType = (5 << 8) | (0 & 0xff) | ((0 & 0xfff00) << 12)
-	ConsoleDevice DeviceType = (5 << 8) | (1 & 0xff) | ((1 & 0xfff00) << 12)
-	PTMXDevice    DeviceType = (5 << 8) | (2 & 0xff) | ((2 & 0xfff00) << 12)
-)
+// Mkdev encodes the device number the same way as the kernel.
+func Mkdev(major uint32, minor uint32) int {
+	return int(uint64(minor&0xff) | uint64(major&0xfff)<<8 | uint64(minor&^0xff)<<12 | uint64(major&^0xfff)<<32)
+}
 
-func (d DeviceType) Path() string {
-	switch d {
-	case NullDevice:
-		return "/dev/null"
-	case ZeroDevice:
-		return "/dev/zero"
-	case FullDevice:
-		return "/dev/full"
-	case RandomDevice:
-		return "/dev/random"
-	case URandomDevice:
-		return "/dev/urandom"
-	case TTYDevice:
-		return "/dev/tty"
-	case ConsoleDevice:
-		return "/dev/console"
-	case PTMXDevice:
-		return "/dev/ptmx"
+func (d Device) resolve(root string) (string, error) {
+	path, err := ResolveInRoot(root, d.Path)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %s: %w", d.Path, err)
 	}
-	return ""
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, nil
 }
 
-func MakeCharacterDevice(d DeviceType) error {
-	return syscall.Mknod(d.Path(), 0666|syscall.S_IFCHR, int(d))
+// Create makes the device node at its path inside root, it fails if the path already exists.
+// The mode is set explicitly, so it is not affected by the umask.
+func (d Device) Create(root string) error {
+	path, err := d.resolve(root)
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Mknod(path, d.Type|d.Mode&07777, Mkdev(d.Major, d.Minor)); err != nil {
+		return fmt.Errorf("mknod: %w", err)
+	}
+	if err := syscall.Chmod(path, d.Mode&07777); err != nil {
+		return fmt.Errorf("chmod: %w", err)
+	}
+	if err := syscall.Chown(path, int(d.Uid), int(d.Gid)); err != nil {
+		return fmt.Errorf("chown: %w", err)
+	}
+	return nil
 }
 
-func SetupCharacterDevices() error {
-	characterDevices := []DeviceType{
-		NullDevice,
-		ZeroDevice,
-		FullDevice,
-		RandomDevice,
-		URandomDevice,
-		TTYDevice,
-		ConsoleDevice,
+// BindHost bind mounts the node of the host at the same path into root. It is used in a user
+// namespace, which does not permit creating device nodes. The node keeps the mode and the owner of the host.
+func (d Device) BindHost(root string) error {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(d.Path, &stat); err != nil {
+		return fmt.Errorf("could not find the host device: %w", err)
+	}
+	if stat.Mode&syscall.S_IFMT != d.Type || Major(stat.Rdev) != d.Major || Minor(stat.Rdev) != d.Minor {
+		return errors.New("the host device does not match")
 	}
 
-	for _, deviceType := range characterDevices {
-		if _, err := os.Stat(deviceType.Path()); err == nil {
-			// TODO: maybe check if the device is ok and leave it
-			if err := os.Remove(deviceType.Path()); err != nil {
-				return err
-			}
-		}
+	path, err := d.resolve(root)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	file.Close()
 
-		if err := MakeCharacterDevice(deviceType); err != nil {
-			return err
-		}
+	if err := syscall.Mount(d.Path, path, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("could not bind mount %s: %w", d.Path, err)
 	}
 	return nil
 }
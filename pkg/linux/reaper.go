@@ -0,0 +1,8 @@
+package linux
+
+const PR_SET_CHILD_SUBREAPER = 36
+
+// SetChildSubreaper makes the orphaned descendants of the calling process its children, instead of the children of the init.
+func SetChildSubreaper() error {
+	return prctl(PR_SET_CHILD_SUBREAPER, 1, 0)
+}
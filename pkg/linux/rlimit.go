@@ -0,0 +1,63 @@
+package linux
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+const (
+	RLIMIT_CPU        = 0
+	RLIMIT_FSIZE      = 1
+	RLIMIT_DATA       = 2
+	RLIMIT_STACK      = 3
+	RLIMIT_CORE       = 4
+	RLIMIT_RSS        = 5
+	RLIMIT_NPROC      = 6
+	RLIMIT_NOFILE     = 7
+	RLIMIT_MEMLOCK    = 8
+	RLIMIT_AS         = 9
+	RLIMIT_LOCKS      = 10
+	RLIMIT_SIGPENDING = 11
+	RLIMIT_MSGQUEUE   = 12
+	RLIMIT_NICE       = 13
+	RLIMIT_RTPRIO     = 14
+	RLIMIT_RTTIME     = 15
+)
+
+var rlimitNames = []string{
+	"RLIMIT_CPU",
+	"RLIMIT_FSIZE",
+	"RLIMIT_DATA",
+	"RLIMIT_STACK",
+	"RLIMIT_CORE",
+	"RLIMIT_RSS",
+	"RLIMIT_NPROC",
+	"RLIMIT_NOFILE",
+	"RLIMIT_MEMLOCK",
+	"RLIMIT_AS",
+	"RLIMIT_LOCKS",
+	"RLIMIT_SIGPENDING",
+	"RLIMIT_MSGQUEUE",
+	"RLIMIT_NICE",
+	"RLIMIT_RTPRIO",
+	"RLIMIT_RTTIME",
+}
+
+// RlimitFromName accepts names with or without the RLIMIT_ prefix, in any case.
+func RlimitFromName(name string) (int, error) {
+	upper := strings.ToUpper(name)
+	if !strings.HasPrefix(upper, "RLIMIT_") {
+		upper = "RLIMIT_" + upper
+	}
+	for i, rlimitName := range rlimitNames {
+		if rlimitName == upper {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown resource limit: %s", name)
+}
+
+func SetRlimit(resource int, soft uint64, hard uint64) error {
+	return syscall.Setrlimit(resource, &syscall.Rlimit{Cur: soft, Max: hard})
+}
@@ -0,0 +1,20 @@
+package linux
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignalFromName(t *testing.T) {
+	for _, name := range []string{"SIGTERM", "term", "15"} {
+		sig, err := SignalFromName(name)
+		require.NoError(t, err)
+		require.Equal(t, syscall.SIGTERM, sig)
+	}
+
+	_, err := SignalFromName("SIGNOPE")
+	require.Error(t, err)
+	require.Equal(t, "SIGKILL", SignalName(syscall.SIGKILL))
+}
@@ -3,6 +3,7 @@ package linux
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"syscall"
 	"unsafe"
@@ -245,8 +246,27 @@ func (t Terminal) OpenSlave() (*os.File, error) {
 	return slaveFile, nil
 }
 
+// Pump copies the input to the master and the output of the terminal to the writer, a nil input is not copied.
+// The returned channel is closed once the output is copied.
+func (t Terminal) Pump(input io.Reader, output io.Writer) <-chan struct{} {
+	copied := make(chan struct{})
+	if input != nil {
+		go func() {
+			_, _ = io.Copy(t.MasterFile, input)
+		}()
+	}
+	go func() {
+		defer close(copied)
+		// Reading the master fails with EIO, once the terminal is closed by all processes.
+		_, _ = io.Copy(output, t.MasterFile)
+	}()
+	return copied
+}
+
+const ptmxPath = "/dev/ptmx"
+
 func NewTerminal() (Terminal, error) {
-	masterFile, err := os.OpenFile(PTMXDevice.Path(), syscall.O_RDWR, 0)
+	masterFile, err := os.OpenFile(ptmxPath, syscall.O_RDWR, 0)
 	if err != nil {
 		return Terminal{}, fmt.Errorf("open error: %s", err)
 	}
@@ -0,0 +1,103 @@
+package logfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string, rotation Rotation, options ReadOptions) []string {
+	var lines []string
+	err := Read(context.Background(), path, rotation, options, func(record Record) error {
+		lines = append(lines, string(record.Stream)+":"+string(record.Line))
+		return nil
+	})
+	require.NoError(t, err)
+	return lines
+}
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	writer, err := Create(path, DefaultRotation)
+	require.NoError(t, err)
+
+	stdout := writer.Stream(StreamStdout)
+	stderr := writer.Stream(StreamStderr)
+	_, err = stdout.Write([]byte("hello\nwor"))
+	require.NoError(t, err)
+	_, err = stderr.Write([]byte("error\xff\n"))
+	require.NoError(t, err)
+	_, err = stdout.Write([]byte("ld\nincomplete"))
+	require.NoError(t, err)
+	require.NoError(t, stdout.Close())
+	require.NoError(t, stderr.Close())
+	require.NoError(t, writer.Close())
+
+	all := ReadOptions{Tail: -1}
+	// The output, which is not valid UTF-8, is kept intact.
+	require.Equal(t, []string{"stdout:hello\n", "stderr:error\xff\n", "stdout:world\n", "stdout:incomplete"}, readLines(t, path, DefaultRotation, all))
+	require.Equal(t, []string{"stdout:world\n", "stdout:incomplete"}, readLines(t, path, DefaultRotation, ReadOptions{Tail: 2}))
+	require.Empty(t, readLines(t, path, DefaultRotation, ReadOptions{Tail: -1, Since: time.Now().Add(time.Hour)}))
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	rotation := Rotation{MaxSize: 256, MaxFiles: 2}
+	writer, err := Create(path, rotation)
+	require.NoError(t, err)
+
+	stdout := writer.Stream(StreamStdout)
+	var expected []string
+	for i := 0; i < 20; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		_, err := stdout.Write([]byte(line))
+		require.NoError(t, err)
+		expected = append(expected, "stdout:"+line)
+	}
+	require.NoError(t, writer.Close())
+
+	// The oldest records are dropped with the files exceeding the limit.
+	lines := readLines(t, path, rotation, ReadOptions{Tail: -1})
+	require.NotEmpty(t, lines)
+	require.Less(t, len(lines), len(expected))
+	require.Equal(t, expected[len(expected)-len(lines):], lines)
+	require.FileExists(t, path+".2")
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	rotation := Rotation{MaxSize: 256, MaxFiles: 1}
+	writer, err := Create(path, rotation)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	stdout := writer.Stream(StreamStdout)
+	go func() {
+		for i := 0; i < 10; i++ {
+			_, _ = stdout.Write([]byte(fmt.Sprintf("line %d\n", i)))
+			time.Sleep(50 * time.Millisecond)
+		}
+		_ = writer.Close()
+		close(done)
+	}()
+
+	lines := readLines(t, path, rotation, ReadOptions{Tail: 0, Follow: true, Done: done})
+	// The records are read once and in order, also across the rotations.
+	require.NotEmpty(t, lines)
+	require.Equal(t, "stdout:line 9\n", lines[len(lines)-1])
+	previous := -1
+	for _, line := range lines {
+		var index int
+		_, err := fmt.Sscanf(line, "stdout:line %d\n", &index)
+		require.NoError(t, err)
+		require.Greater(t, index, previous)
+		previous = index
+	}
+}
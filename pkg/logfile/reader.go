@@ -0,0 +1,204 @@
+package logfile
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+const pollInterval = 250 * time.Millisecond
+
+type ReadOptions struct {
+	// Since skips the records written before it.
+	Since time.Time
+	// Tail limits the records to the last ones, a negative value returns all of them.
+	Tail int
+	// Follow keeps reading the records as they are written, until Done is closed.
+	Follow bool
+	Done   <-chan struct{}
+}
+
+// Read calls fn for the records of the log and its rotated files in the order they were written.
+func Read(ctx context.Context, path string, rotation Rotation, options ReadOptions, fn func(Record) error) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	emit := fn
+	var tail []Record
+	if options.Tail >= 0 {
+		emit = func(record Record) error {
+			if options.Tail == 0 {
+				return nil
+			}
+			if len(tail) == options.Tail {
+				tail = tail[1:]
+			}
+			tail = append(tail, record)
+			return nil
+		}
+	}
+	filtered := func(record Record) error {
+		if record.Time.Before(options.Since) {
+			return nil
+		}
+		return emit(record)
+	}
+
+	for i := rotation.MaxFiles; i >= 1; i-- {
+		if err := readFile(rotatedPath(path, i), filtered); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("readFile: %w", err)
+		}
+	}
+
+	follower, err := openFollower(path)
+	if err != nil {
+		return fmt.Errorf("openFollower: %w", err)
+	}
+	defer follower.close()
+
+	if err := follower.readAvailable(filtered); err != nil {
+		return fmt.Errorf("follower.readAvailable: %w", err)
+	}
+
+	for _, record := range tail {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if !options.Follow {
+		return nil
+	}
+
+	filtered = func(record Record) error {
+		if record.Time.Before(options.Since) {
+			return nil
+		}
+		return fn(record)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		done := false
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-options.Done:
+			done = true
+		case <-ticker.C:
+		}
+
+		// The last records are read after the writer is closed.
+		if err := follower.follow(path, filtered); err != nil {
+			return fmt.Errorf("follower.follow: %w", err)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+func readFile(path string, fn func(Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return readRecords(bufio.NewReader(file), nil, fn)
+}
+
+// readRecords reads complete lines and returns the incomplete rest, if the reader is a file still being written.
+func readRecords(reader *bufio.Reader, partial *[]byte, fn func(Record) error) error {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if partial != nil && len(*partial) > 0 {
+			line = append(*partial, line...)
+			*partial = nil
+		}
+		if errors.Is(err, io.EOF) {
+			if partial != nil && len(line) > 0 {
+				*partial = line
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// follower reads the current file as it grows and switches to the new one after a rotation.
+// The records of a file, which was rotated out between two polls, are skipped.
+type follower struct {
+	file    *os.File
+	reader  *bufio.Reader
+	inode   uint64
+	partial []byte
+}
+
+func inode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+	return 0
+}
+
+func openFollower(path string) (*follower, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &follower{file: file, reader: bufio.NewReader(file), inode: inode(info)}, nil
+}
+
+func (f *follower) close() {
+	f.file.Close()
+}
+
+func (f *follower) readAvailable(fn func(Record) error) error {
+	return readRecords(f.reader, &f.partial, fn)
+}
+
+func (f *follower) follow(path string, fn func(Record) error) error {
+	if err := f.readAvailable(fn); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || inode(info) == f.inode {
+		return nil
+	}
+
+	// The file was rotated, the old one is complete.
+	if err := f.readAvailable(fn); err != nil {
+		return err
+	}
+	next, err := openFollower(path)
+	if err != nil {
+		return nil
+	}
+	f.close()
+	*f = *next
+	return f.readAvailable(fn)
+}
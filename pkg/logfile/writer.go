@@ -0,0 +1,191 @@
+package logfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type Stream string
+
+const (
+	StreamStdout Stream = "stdout"
+	StreamStderr Stream = "stderr"
+)
+
+// maxLineSize limits the buffered part of a line, longer lines are split into several records.
+const maxLineSize = 16 << 10
+
+// Record is a line of the output of a process, Line keeps the trailing newline if the line was complete.
+// The output does not have to be valid UTF-8, so the line is stored as base64 in json.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Stream Stream    `json:"stream"`
+	Line   []byte    `json:"line"`
+}
+
+type Rotation struct {
+	// MaxSize is the size, after which the file is rotated.
+	MaxSize int64
+	// MaxFiles is the number of rotated files kept next to the current one.
+	MaxFiles int
+}
+
+var DefaultRotation = Rotation{
+	MaxSize:  10 << 20,
+	MaxFiles: 3,
+}
+
+func rotatedPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+// Remove removes the log file and its rotated files.
+func Remove(path string, rotation Rotation) error {
+	for i := rotation.MaxFiles; i >= 1; i-- {
+		if err := os.Remove(rotatedPath(path, i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Writer stores the records as json lines and rotates the file, once it grows over the size limit.
+type Writer struct {
+	path     string
+	rotation Rotation
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Create starts a new log, the files of a previous log with the same path are removed.
+func Create(path string, rotation Rotation) (*Writer, error) {
+	if err := Remove(path, rotation); err != nil {
+		return nil, fmt.Errorf("Remove: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{path: path, rotation: rotation, file: file}, nil
+}
+
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("w.file.Close: %w", err)
+	}
+
+	if w.rotation.MaxFiles > 0 {
+		for i := w.rotation.MaxFiles - 1; i >= 1; i-- {
+			if err := os.Rename(rotatedPath(w.path, i), rotatedPath(w.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("os.Rename: %w", err)
+			}
+		}
+		if err := os.Rename(w.path, rotatedPath(w.path, 1)); err != nil {
+			return fmt.Errorf("os.Rename: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.size = 0
+	return nil
+}
+
+func (w *Writer) write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	data = append(data, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(data)) > w.rotation.MaxSize {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("w.rotate: %w", err)
+		}
+	}
+
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return err
+}
+
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// Stream returns a writer, which splits the output of the stream into records.
+// It has to be closed to store the last incomplete line.
+func (w *Writer) Stream(stream Stream) io.WriteCloser {
+	return &lineWriter{writer: w, stream: stream}
+}
+
+type lineWriter struct {
+	writer *Writer
+	stream Stream
+
+	mu     sync.Mutex
+	buffer []byte
+}
+
+func (l *lineWriter) emit(line []byte) error {
+	return l.writer.write(Record{Time: time.Now().UTC(), Stream: l.stream, Line: line})
+}
+
+func (l *lineWriter) Write(data []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buffer = append(l.buffer, data...)
+	for {
+		index := bytes.IndexByte(l.buffer, '\n')
+		if index < 0 {
+			break
+		}
+		if err := l.emit(l.buffer[:index+1]); err != nil {
+			return 0, err
+		}
+		l.buffer = l.buffer[index+1:]
+	}
+
+	for len(l.buffer) >= maxLineSize {
+		if err := l.emit(l.buffer[:maxLineSize]); err != nil {
+			return 0, err
+		}
+		l.buffer = l.buffer[maxLineSize:]
+	}
+
+	// The remaining part is copied, so the buffer does not keep growing.
+	l.buffer = append([]byte(nil), l.buffer...)
+	return len(data), nil
+}
+
+func (l *lineWriter) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.buffer) == 0 {
+		return nil
+	}
+	err := l.emit(l.buffer)
+	l.buffer = nil
+	return err
+}
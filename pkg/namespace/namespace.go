@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
 	"syscall"
 
 	"github.com/mmbednarek/fragma/pkg/linux"
@@ -96,3 +97,33 @@ func SetNamespace(ns *Namespace) error {
 
 	return nil
 }
+
+// Run calls fn on a thread that joined the namespace. The thread is restored afterwards,
+// if it cannot be restored, it stays locked and exits together with the goroutine.
+func Run(ns *Namespace, fn func() error) error {
+	errs := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		current, err := syscall.Open(fmt.Sprintf("/proc/self/task/%d/ns/%s", syscall.Gettid(), ns.Kind), syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			errs <- fmt.Errorf("syscall.Open: %w", err)
+			return
+		}
+		defer syscall.Close(current)
+
+		if err := SetNamespace(ns); err != nil {
+			runtime.UnlockOSThread()
+			errs <- fmt.Errorf("SetNamespace: %w", err)
+			return
+		}
+
+		errs <- fn()
+
+		if err := linux.Setns(current, 0); err != nil {
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+	return <-errs
+}
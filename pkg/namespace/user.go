@@ -0,0 +1,70 @@
+package namespace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+type IDMap struct {
+	ContainerID int
+	HostID      int
+	Size        int
+}
+
+func readIDMap(pid process.Pid, name string) ([]IDMap, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/%s", pid, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var result []IDMap
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid %s line: %q", name, scanner.Text())
+		}
+
+		var values [3]int
+		for i, field := range fields {
+			value, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value: %q", name, field)
+			}
+			values[i] = int(value)
+		}
+
+		result = append(result, IDMap{ContainerID: values[0], HostID: values[1], Size: values[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReadUidMap reads the uid mappings of the user namespace the process belongs to.
+func ReadUidMap(pid process.Pid) ([]IDMap, error) {
+	return readIDMap(pid, "uid_map")
+}
+
+// ReadGidMap reads the gid mappings of the user namespace the process belongs to.
+func ReadGidMap(pid process.Pid) ([]IDMap, error) {
+	return readIDMap(pid, "gid_map")
+}
+
+// HostID translates an id of the namespace to the host.
+func HostID(mappings []IDMap, id int) (int, bool) {
+	for _, mapping := range mappings {
+		if id >= mapping.ContainerID && id < mapping.ContainerID+mapping.Size {
+			return mapping.HostID + id - mapping.ContainerID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrSubnetExhausted = errors.New("no free addresses left in the subnet")
+	ErrInvalidSubnet   = errors.New("invalid subnet")
+)
+
+// Store keeps the allocated addresses, it is implemented by storage.Storage.
+type Store interface {
+	WriteValue(key string, value []byte) error
+	ReadAllValues(prefix string) (map[string][]byte, error)
+	RemoveValue(key string) error
+}
+
+// Allocation is an address reserved for an owner.
+type Allocation struct {
+	IP    net.IP
+	Owner string
+}
+
+// Allocator hands out the host addresses of an ipv4 subnet. The first address is reserved for the gateway.
+type Allocator struct {
+	mu     sync.Mutex
+	store  Store
+	prefix string
+	subnet *net.IPNet
+}
+
+func NewAllocator(store Store, subnet string) (*Allocator, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("net.ParseCIDR: %w", err)
+	}
+	if ipNet.IP.To4() == nil {
+		return nil, fmt.Errorf("%w: %s is not ipv4", ErrInvalidSubnet, subnet)
+	}
+	if ones, _ := ipNet.Mask.Size(); ones > 30 {
+		return nil, fmt.Errorf("%w: %s is too small", ErrInvalidSubnet, subnet)
+	}
+
+	return &Allocator{
+		store:  store,
+		prefix: fmt.Sprintf("network/ipam/%s/", ipNet),
+		subnet: ipNet,
+	}, nil
+}
+
+func ipToUint(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func uintToIP(value uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, value)
+	return ip
+}
+
+func (a *Allocator) Subnet() net.IPNet {
+	return *a.subnet
+}
+
+func (a *Allocator) Gateway() net.IP {
+	return uintToIP(ipToUint(a.subnet.IP) + 1)
+}
+
+// Allocate reserves a free address for the owner.
+func (a *Allocator) Allocate(owner string) (net.IP, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	used, err := a.store.ReadAllValues(a.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("a.store.ReadAllValues: %w", err)
+	}
+
+	ones, bits := a.subnet.Mask.Size()
+	first := ipToUint(a.subnet.IP) + 2
+	broadcast := ipToUint(a.subnet.IP) + (1 << uint(bits-ones)) - 1
+
+	for value := first; value < broadcast; value++ {
+		ip := uintToIP(value)
+		if _, ok := used[a.prefix+ip.String()]; ok {
+			continue
+		}
+		if err := a.store.WriteValue(a.prefix+ip.String(), []byte(owner)); err != nil {
+			return nil, fmt.Errorf("a.store.WriteValue: %w", err)
+		}
+		return ip, nil
+	}
+
+	return nil, ErrSubnetExhausted
+}
+
+func (a *Allocator) Release(ip net.IP) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.store.RemoveValue(a.prefix + ip.String()); err != nil {
+		return fmt.Errorf("a.store.RemoveValue: %w", err)
+	}
+	return nil
+}
+
+// Allocations returns the reserved addresses ordered by the address.
+func (a *Allocator) Allocations() ([]Allocation, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	used, err := a.store.ReadAllValues(a.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("a.store.ReadAllValues: %w", err)
+	}
+
+	allocations := make([]Allocation, 0, len(used))
+	for key, owner := range used {
+		ip := net.ParseIP(strings.TrimPrefix(key, a.prefix)).To4()
+		if ip == nil {
+			continue
+		}
+		allocations = append(allocations, Allocation{IP: ip, Owner: string(owner)})
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return ipToUint(allocations[i].IP) < ipToUint(allocations[j].IP)
+	})
+	return allocations, nil
+}
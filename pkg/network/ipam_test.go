@@ -0,0 +1,84 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore map[string][]byte
+
+func (m memoryStore) WriteValue(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+func (m memoryStore) ReadAllValues(prefix string) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	for key, value := range m {
+		if strings.HasPrefix(key, prefix) {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+func (m memoryStore) RemoveValue(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestAllocator_Allocate(t *testing.T) {
+	store := memoryStore{}
+	allocator, err := NewAllocator(store, "10.1.2.0/29")
+	require.NoError(t, err)
+	require.Equal(t, "10.1.2.1", allocator.Gateway().String())
+
+	var ips []string
+	for i := 0; i < 5; i++ {
+		ip, err := allocator.Allocate("app")
+		require.NoError(t, err)
+		ips = append(ips, ip.String())
+	}
+	require.Equal(t, []string{"10.1.2.2", "10.1.2.3", "10.1.2.4", "10.1.2.5", "10.1.2.6"}, ips)
+
+	_, err = allocator.Allocate("app")
+	require.True(t, errors.Is(err, ErrSubnetExhausted))
+
+	require.NoError(t, allocator.Release(net.ParseIP("10.1.2.4")))
+
+	// The allocations survive a restart of the allocator.
+	allocator, err = NewAllocator(store, "10.1.2.0/29")
+	require.NoError(t, err)
+	ip, err := allocator.Allocate("app")
+	require.NoError(t, err)
+	require.Equal(t, "10.1.2.4", ip.String())
+}
+
+func TestNewAllocator_Invalid(t *testing.T) {
+	_, err := NewAllocator(memoryStore{}, "10.1.2.0/31")
+	require.True(t, errors.Is(err, ErrInvalidSubnet))
+
+	_, err = NewAllocator(memoryStore{}, "fd00::/64")
+	require.True(t, errors.Is(err, ErrInvalidSubnet))
+}
+
+func TestAllocator_Allocations(t *testing.T) {
+	allocator, err := NewAllocator(memoryStore{}, "10.1.2.0/24")
+	require.NoError(t, err)
+	for _, owner := range []string{"web", "db", "cache"} {
+		_, err := allocator.Allocate(owner)
+		require.NoError(t, err)
+	}
+	require.NoError(t, allocator.Release(net.ParseIP("10.1.2.3")))
+
+	allocations, err := allocator.Allocations()
+	require.NoError(t, err)
+	require.Equal(t, []Allocation{
+		{IP: net.ParseIP("10.1.2.2").To4(), Owner: "web"},
+		{IP: net.ParseIP("10.1.2.4").To4(), Owner: "cache"},
+	}, allocations)
+}
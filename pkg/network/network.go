@@ -0,0 +1,290 @@
+package network
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/mmbednarek/fragma/pkg/linux"
+	"github.com/mmbednarek/fragma/pkg/namespace"
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+const (
+	DefaultBridge      = "fragma0"
+	DefaultSubnet      = "10.88.0.0/16"
+	ContainerInterface = "eth0"
+
+	// maxInterfaceName is IFNAMSIZ without the terminating null byte.
+	maxInterfaceName    = 15
+	hostInterfacePrefix = "fv"
+)
+
+// Attachment is the connection of a container to the bridge.
+type Attachment struct {
+	Address       net.IPNet
+	Gateway       net.IP
+	HostInterface string
+}
+
+// Leak is an address or a host interface left behind by a run, which no longer exists.
+// Exactly one of Address and Interface is set.
+type Leak struct {
+	Owner     string
+	Address   net.IP
+	Interface string
+}
+
+type Manager struct {
+	bridge    string
+	allocator *Allocator
+}
+
+func NewManager(bridge string, allocator *Allocator) *Manager {
+	return &Manager{
+		bridge:    bridge,
+		allocator: allocator,
+	}
+}
+
+func runInNetworkNamespace(pid int, fn func(nl *linux.Netlink) error) error {
+	ns, err := namespace.FindNamespaceByPid(namespace.ResourceNetwork, process.Pid(pid))
+	if err != nil {
+		return fmt.Errorf("namespace.FindNamespaceByPid: %w", err)
+	}
+
+	return namespace.Run(&ns, func() error {
+		// The socket has to be opened after joining the namespace.
+		nl, err := linux.OpenNetlink()
+		if err != nil {
+			return fmt.Errorf("linux.OpenNetlink: %w", err)
+		}
+		defer nl.Close()
+
+		return fn(nl)
+	})
+}
+
+// SetupLoopback brings up the loopback device in the network namespace of the process.
+func SetupLoopback(pid int) error {
+	return runInNetworkNamespace(pid, func(nl *linux.Netlink) error {
+		lo, err := net.InterfaceByName("lo")
+		if err != nil {
+			return fmt.Errorf("net.InterfaceByName: %w", err)
+		}
+		if err := nl.SetLinkUp(lo.Index); err != nil {
+			return fmt.Errorf("nl.SetLinkUp: %w", err)
+		}
+		return nil
+	})
+}
+
+// EnsureBridge creates the bridge if it does not exist and assigns it the gateway address.
+func (m *Manager) EnsureBridge() error {
+	nl, err := linux.OpenNetlink()
+	if err != nil {
+		return fmt.Errorf("linux.OpenNetlink: %w", err)
+	}
+	defer nl.Close()
+
+	bridge, err := net.InterfaceByName(m.bridge)
+	if err != nil {
+		if err := nl.CreateBridge(m.bridge); err != nil {
+			return fmt.Errorf("nl.CreateBridge: %w", err)
+		}
+		bridge, err = net.InterfaceByName(m.bridge)
+		if err != nil {
+			return fmt.Errorf("net.InterfaceByName: %w", err)
+		}
+	}
+
+	subnet := m.allocator.Subnet()
+	if err := nl.AddAddress(bridge.Index, net.IPNet{IP: m.allocator.Gateway(), Mask: subnet.Mask}); err != nil {
+		return fmt.Errorf("nl.AddAddress: %w", err)
+	}
+
+	if err := nl.SetLinkUp(bridge.Index); err != nil {
+		return fmt.Errorf("nl.SetLinkUp: %w", err)
+	}
+	return nil
+}
+
+// hostInterfaceName derives the name from a hash of the run name, so the long names sharing a prefix do not collide.
+func hostInterfaceName(name string) string {
+	sum := sha1.Sum([]byte(name))
+	return hostInterfacePrefix + hex.EncodeToString(sum[:])[:maxInterfaceName-len(hostInterfacePrefix)]
+}
+
+// isHostInterface reports whether the interface name was derived by hostInterfaceName.
+func isHostInterface(name string) bool {
+	suffix, ok := strings.CutPrefix(name, hostInterfacePrefix)
+	if !ok || len(name) != maxInterfaceName {
+		return false
+	}
+	return len(strings.Trim(suffix, "0123456789abcdef")) == 0
+}
+
+// findLeaks returns the host interfaces and the addresses, which do not belong to any of the live runs.
+func findLeaks(allocations []Allocation, interfaces []string, live map[string]bool) []Leak {
+	owners := map[string]string{}
+	liveInterfaces := map[string]bool{}
+	for _, allocation := range allocations {
+		owners[hostInterfaceName(allocation.Owner)] = allocation.Owner
+	}
+	for name := range live {
+		liveInterfaces[hostInterfaceName(name)] = true
+	}
+
+	// The interfaces are removed before their addresses are released.
+	var leaks []Leak
+	for _, name := range interfaces {
+		if isHostInterface(name) && !liveInterfaces[name] {
+			leaks = append(leaks, Leak{Owner: owners[name], Interface: name})
+		}
+	}
+	for _, allocation := range allocations {
+		if !live[allocation.Owner] {
+			leaks = append(leaks, Leak{Owner: allocation.Owner, Address: allocation.IP})
+		}
+	}
+	return leaks
+}
+
+// FindLeaks returns the network resources left behind by the runs, which are not live, for example after a crash of the daemon.
+// No run can be attached in the meantime, otherwise its address could be taken for a leak.
+func (m *Manager) FindLeaks(live map[string]bool) ([]Leak, error) {
+	allocations, err := m.allocator.Allocations()
+	if err != nil {
+		return nil, fmt.Errorf("m.allocator.Allocations: %w", err)
+	}
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("net.Interfaces: %w", err)
+	}
+
+	names := make([]string, 0, len(interfaces))
+	for _, iface := range interfaces {
+		names = append(names, iface.Name)
+	}
+	return findLeaks(allocations, names, live), nil
+}
+
+// ReleaseLeak removes the host interface or releases the address of the leak.
+func (m *Manager) ReleaseLeak(leak Leak) error {
+	if len(leak.Interface) != 0 {
+		return m.deleteHostInterface(leak.Interface)
+	}
+	if err := m.allocator.Release(leak.Address); err != nil {
+		return fmt.Errorf("m.allocator.Release: %w", err)
+	}
+	return nil
+}
+
+// Attach connects the network namespace of the process to the bridge with a veth pair.
+func (m *Manager) Attach(name string, pid int) (*Attachment, error) {
+	if err := m.EnsureBridge(); err != nil {
+		return nil, fmt.Errorf("m.EnsureBridge: %w", err)
+	}
+
+	ip, err := m.allocator.Allocate(name)
+	if err != nil {
+		return nil, fmt.Errorf("m.allocator.Allocate: %w", err)
+	}
+
+	subnet := m.allocator.Subnet()
+	attachment := &Attachment{
+		Address:       net.IPNet{IP: ip, Mask: subnet.Mask},
+		Gateway:       m.allocator.Gateway(),
+		HostInterface: hostInterfaceName(name),
+	}
+
+	if err := m.connect(attachment, pid); err != nil {
+		if detachErr := m.Detach(attachment); detachErr != nil {
+			return nil, fmt.Errorf("%w (detach: %s)", err, detachErr)
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (m *Manager) connect(attachment *Attachment, pid int) error {
+	nl, err := linux.OpenNetlink()
+	if err != nil {
+		return fmt.Errorf("linux.OpenNetlink: %w", err)
+	}
+	defer nl.Close()
+
+	if err := nl.CreateVethPair(attachment.HostInterface, ContainerInterface, pid); err != nil {
+		return fmt.Errorf("nl.CreateVethPair: %w", err)
+	}
+
+	host, err := net.InterfaceByName(attachment.HostInterface)
+	if err != nil {
+		return fmt.Errorf("net.InterfaceByName: %w", err)
+	}
+	bridge, err := net.InterfaceByName(m.bridge)
+	if err != nil {
+		return fmt.Errorf("net.InterfaceByName: %w", err)
+	}
+
+	if err := nl.SetLinkMaster(host.Index, bridge.Index); err != nil {
+		return fmt.Errorf("nl.SetLinkMaster: %w", err)
+	}
+	if err := nl.SetLinkUp(host.Index); err != nil {
+		return fmt.Errorf("nl.SetLinkUp: %w", err)
+	}
+
+	return runInNetworkNamespace(pid, func(nl *linux.Netlink) error {
+		container, err := net.InterfaceByName(ContainerInterface)
+		if err != nil {
+			return fmt.Errorf("net.InterfaceByName: %w", err)
+		}
+		if err := nl.AddAddress(container.Index, attachment.Address); err != nil {
+			return fmt.Errorf("nl.AddAddress: %w", err)
+		}
+		if err := nl.SetLinkUp(container.Index); err != nil {
+			return fmt.Errorf("nl.SetLinkUp: %w", err)
+		}
+		if err := nl.AddDefaultRoute(attachment.Gateway); err != nil {
+			return fmt.Errorf("nl.AddDefaultRoute: %w", err)
+		}
+		return nil
+	})
+}
+
+// Detach removes the host end of the veth pair and releases the address. The host
+// end is usually already gone, as the pair is destroyed together with the namespace.
+func (m *Manager) Detach(attachment *Attachment) error {
+	if err := m.deleteHostInterface(attachment.HostInterface); err != nil {
+		return err
+	}
+	if err := m.allocator.Release(attachment.Address.IP); err != nil {
+		return fmt.Errorf("m.allocator.Release: %w", err)
+	}
+	return nil
+}
+
+// deleteHostInterface removes the host end of a veth pair, it does nothing if the interface is already gone.
+func (m *Manager) deleteHostInterface(name string) error {
+	host, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil
+	}
+
+	nl, err := linux.OpenNetlink()
+	if err != nil {
+		return fmt.Errorf("linux.OpenNetlink: %w", err)
+	}
+	defer nl.Close()
+
+	var linuxErr linux.Error
+	if err := nl.DeleteLink(host.Index); err != nil && !(errors.As(err, &linuxErr) && linuxErr.Errno == syscall.ENODEV) {
+		return fmt.Errorf("nl.DeleteLink: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostInterfaceName(t *testing.T) {
+	first := hostInterfaceName("application-worker-1")
+	second := hostInterfaceName("application-worker-2")
+	require.NotEqual(t, first, second)
+	require.Len(t, first, maxInterfaceName)
+	require.Equal(t, first, hostInterfaceName("application-worker-1"))
+}
+
+func TestFindLeaks(t *testing.T) {
+	allocations := []Allocation{
+		{IP: net.ParseIP("10.1.2.2").To4(), Owner: "web"},
+		{IP: net.ParseIP("10.1.2.3").To4(), Owner: "db"},
+	}
+	interfaces := []string{"lo", "fragma0", "fvcustom", hostInterfaceName("web"), hostInterfaceName("db"), hostInterfaceName("gone")}
+
+	leaks := findLeaks(allocations, interfaces, map[string]bool{"web": true})
+	require.Equal(t, []Leak{
+		{Owner: "db", Interface: hostInterfaceName("db")},
+		{Interface: hostInterfaceName("gone")},
+		{Owner: "db", Address: net.ParseIP("10.1.2.3").To4()},
+	}, leaks)
+}
@@ -0,0 +1,262 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mmbednarek/fragma/pkg/namespace"
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+const (
+	dialTimeout       = 5 * time.Second
+	udpSessionTimeout = 30 * time.Second
+	udpBufferSize     = 65535
+)
+
+type Protocol string
+
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
+
+var ErrInvalidPortMapping = errors.New("invalid port mapping")
+
+// PortMapping publishes a port of the container on the host.
+type PortMapping struct {
+	Protocol      Protocol
+	HostIP        net.IP
+	HostPort      uint16
+	ContainerPort uint16
+}
+
+func (m PortMapping) hostAddress() string {
+	host := ""
+	if m.HostIP != nil {
+		host = m.HostIP.String()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(m.HostPort)))
+}
+
+func (m PortMapping) containerAddress() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(int(m.ContainerPort)))
+}
+
+// ValidatePortMappings rejects mappings with zero ports and mappings publishing the same host port twice.
+func ValidatePortMappings(mappings []PortMapping) error {
+	used := map[string]struct{}{}
+	for _, mapping := range mappings {
+		if mapping.HostPort == 0 || mapping.ContainerPort == 0 {
+			return fmt.Errorf("%w: ports must not be zero", ErrInvalidPortMapping)
+		}
+		if mapping.Protocol != ProtocolTCP && mapping.Protocol != ProtocolUDP {
+			return fmt.Errorf("%w: unknown protocol %s", ErrInvalidPortMapping, mapping.Protocol)
+		}
+
+		key := string(mapping.Protocol) + "/" + strconv.Itoa(int(mapping.HostPort))
+		if _, ok := used[key]; ok {
+			return fmt.Errorf("%w: host port %s is published twice", ErrInvalidPortMapping, key)
+		}
+		used[key] = struct{}{}
+	}
+	return nil
+}
+
+// Proxy forwards the connections from the host ports to the loopback of the container.
+// It dials from inside the network namespace, so it works without the bridge.
+type Proxy struct {
+	ns       namespace.Namespace
+	closers  []io.Closer
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	sessions map[string]*net.UDPConn
+	closed   bool
+}
+
+// StartProxy listens on all the host ports, nothing is published if any of them fails.
+func StartProxy(pid int, mappings []PortMapping) (*Proxy, error) {
+	ns, err := namespace.FindNamespaceByPid(namespace.ResourceNetwork, process.Pid(pid))
+	if err != nil {
+		return nil, fmt.Errorf("namespace.FindNamespaceByPid: %w", err)
+	}
+
+	p := &Proxy{
+		ns:       ns,
+		sessions: map[string]*net.UDPConn{},
+	}
+
+	for _, mapping := range mappings {
+		if err := p.publish(mapping); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Proxy) publish(mapping PortMapping) error {
+	switch mapping.Protocol {
+	case ProtocolTCP:
+		listener, err := net.Listen("tcp", mapping.hostAddress())
+		if err != nil {
+			return fmt.Errorf("net.Listen: %w", err)
+		}
+		p.closers = append(p.closers, listener)
+		p.wg.Add(1)
+		go p.serveTCP(listener, mapping)
+	case ProtocolUDP:
+		conn, err := net.ListenPacket("udp", mapping.hostAddress())
+		if err != nil {
+			return fmt.Errorf("net.ListenPacket: %w", err)
+		}
+		p.closers = append(p.closers, conn)
+		p.wg.Add(1)
+		go p.serveUDP(conn.(*net.UDPConn), mapping)
+	default:
+		return fmt.Errorf("%w: unknown protocol %s", ErrInvalidPortMapping, mapping.Protocol)
+	}
+	return nil
+}
+
+func (p *Proxy) dial(network string, address string) (net.Conn, error) {
+	var conn net.Conn
+	err := namespace.Run(&p.ns, func() error {
+		// The socket belongs to the namespace it was created in, even after the thread leaves it.
+		var err error
+		conn, err = net.DialTimeout(network, address, dialTimeout)
+		return err
+	})
+	return conn, err
+}
+
+func (p *Proxy) serveTCP(listener net.Listener, mapping PortMapping) {
+	defer p.wg.Done()
+	for {
+		client, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer client.Close()
+
+			backend, err := p.dial("tcp", mapping.containerAddress())
+			if err != nil {
+				return
+			}
+			defer backend.Close()
+
+			done := make(chan struct{}, 2)
+			go func() {
+				_, _ = io.Copy(backend, client)
+				closeWrite(backend)
+				done <- struct{}{}
+			}()
+			go func() {
+				_, _ = io.Copy(client, backend)
+				closeWrite(client)
+				done <- struct{}{}
+			}()
+			<-done
+			<-done
+		}()
+	}
+}
+
+func closeWrite(conn net.Conn) {
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.CloseWrite()
+	}
+}
+
+// serveUDP keeps a connected socket inside the namespace for every client, so the replies can be routed back.
+func (p *Proxy) serveUDP(conn *net.UDPConn, mapping PortMapping) {
+	defer p.wg.Done()
+	buffer := make([]byte, udpBufferSize)
+	for {
+		size, client, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		session, err := p.udpSession(conn, client, mapping)
+		if err != nil {
+			continue
+		}
+		_ = session.SetDeadline(time.Now().Add(udpSessionTimeout))
+		_, _ = session.Write(buffer[:size])
+	}
+}
+
+func (p *Proxy) udpSession(conn *net.UDPConn, client *net.UDPAddr, mapping PortMapping) (*net.UDPConn, error) {
+	key := string(mapping.Protocol) + "/" + strconv.Itoa(int(mapping.HostPort)) + "/" + client.String()
+
+	p.mu.Lock()
+	session, ok := p.sessions[key]
+	p.mu.Unlock()
+	if ok {
+		return session, nil
+	}
+
+	backend, err := p.dial("udp", mapping.containerAddress())
+	if err != nil {
+		return nil, err
+	}
+	session = backend.(*net.UDPConn)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		session.Close()
+		return nil, net.ErrClosed
+	}
+	p.sessions[key] = session
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			p.mu.Lock()
+			delete(p.sessions, key)
+			p.mu.Unlock()
+			session.Close()
+		}()
+
+		buffer := make([]byte, udpBufferSize)
+		for {
+			size, err := session.Read(buffer)
+			if err != nil {
+				return
+			}
+			_ = session.SetDeadline(time.Now().Add(udpSessionTimeout))
+			if _, err := conn.WriteToUDP(buffer[:size], client); err != nil {
+				return
+			}
+		}
+	}()
+
+	return session, nil
+}
+
+// Close stops listening on the host ports and drops the udp sessions. The established
+// tcp connections are not interrupted, they end as soon as the container exits.
+func (p *Proxy) Close() {
+	p.mu.Lock()
+	p.closed = true
+	for _, session := range p.sessions {
+		session.Close()
+	}
+	p.mu.Unlock()
+
+	for _, closer := range p.closers {
+		_ = closer.Close()
+	}
+	p.wg.Wait()
+}
@@ -0,0 +1,104 @@
+package network
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func freePort(t *testing.T, network string) uint16 {
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		return uint16(conn.LocalAddr().(*net.UDPAddr).Port)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return uint16(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestValidatePortMappings(t *testing.T) {
+	require.NoError(t, ValidatePortMappings([]PortMapping{
+		{Protocol: ProtocolTCP, HostPort: 8080, ContainerPort: 80},
+		{Protocol: ProtocolUDP, HostPort: 8080, ContainerPort: 80},
+	}))
+
+	err := ValidatePortMappings([]PortMapping{
+		{Protocol: ProtocolTCP, HostPort: 8080, ContainerPort: 80},
+		{Protocol: ProtocolTCP, HostPort: 8080, ContainerPort: 81},
+	})
+	require.True(t, errors.Is(err, ErrInvalidPortMapping))
+
+	err = ValidatePortMappings([]PortMapping{{Protocol: ProtocolTCP, HostPort: 8080}})
+	require.True(t, errors.Is(err, ErrInvalidPortMapping))
+}
+
+// The proxy dials into the namespace of the test process, which is enough to check the forwarding.
+func TestProxy(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("joining a network namespace requires root")
+	}
+
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backend.Close()
+	go func() {
+		conn, err := backend.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	udpBackend, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udpBackend.Close()
+	go func() {
+		buffer := make([]byte, 64)
+		size, addr, err := udpBackend.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		_, _ = udpBackend.WriteTo(buffer[:size], addr)
+	}()
+
+	tcpPort := freePort(t, "tcp")
+	udpPort := freePort(t, "udp")
+	proxy, err := StartProxy(os.Getpid(), []PortMapping{
+		{Protocol: ProtocolTCP, HostIP: net.ParseIP("127.0.0.1"), HostPort: tcpPort, ContainerPort: uint16(backend.Addr().(*net.TCPAddr).Port)},
+		{Protocol: ProtocolUDP, HostIP: net.ParseIP("127.0.0.1"), HostPort: udpPort, ContainerPort: uint16(udpBackend.LocalAddr().(*net.UDPAddr).Port)},
+	})
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(tcpPort))))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(reply))
+	conn.Close()
+
+	udpConn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(udpPort))))
+	require.NoError(t, err)
+	defer udpConn.Close()
+	_, err = udpConn.Write([]byte("pong"))
+	require.NoError(t, err)
+	size, err := udpConn.Read(reply)
+	require.NoError(t, err)
+	require.Equal(t, "pong", string(reply[:size]))
+
+	proxy.Close()
+	_, err = net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(tcpPort))))
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+const hostResolvConf = "/etc/resolv.conf"
+
+// FallbackNameservers are used if the host does not have any nameserver reachable from a container.
+var FallbackNameservers = []string{"8.8.8.8", "8.8.4.4"}
+
+// HostNameservers returns the nameservers of the host. Loopback servers such as
+// the systemd-resolved stub are skipped, as they are not reachable from the container namespace.
+func HostNameservers() []string {
+	file, err := os.Open(hostResolvConf)
+	if err != nil {
+		return FallbackNameservers
+	}
+	defer file.Close()
+
+	var result []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		ip := net.ParseIP(fields[1])
+		if ip == nil || ip.IsLoopback() {
+			continue
+		}
+		result = append(result, fields[1])
+	}
+
+	if len(result) == 0 {
+		return FallbackNameservers
+	}
+	return result
+}
+
+func ResolvConf(nameservers []string, search []string) string {
+	var builder strings.Builder
+	if len(search) > 0 {
+		_, _ = fmt.Fprintf(&builder, "search %s\n", strings.Join(search, " "))
+	}
+	for _, server := range nameservers {
+		_, _ = fmt.Fprintf(&builder, "nameserver %s\n", server)
+	}
+	return builder.String()
+}
+
+// Hosts returns the hosts file of a container, ip may be nil if the container has only the loopback device.
+func Hosts(hostname string, ip net.IP) string {
+	var builder strings.Builder
+	builder.WriteString("127.0.0.1\tlocalhost\n")
+	builder.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+	if ip != nil {
+		_, _ = fmt.Fprintf(&builder, "%s\t%s\n", ip, hostname)
+	} else {
+		_, _ = fmt.Fprintf(&builder, "127.0.1.1\t%s\n", hostname)
+	}
+	return builder.String()
+}
+
+// WriteResolvConf writes the resolv.conf of a container to a file kept by the daemon, outside of the container root,
+// so the symlinks of the image cannot redirect the write to the host.
+func WriteResolvConf(path string, nameservers []string, search []string) error {
+	if err := os.WriteFile(path, []byte(ResolvConf(nameservers, search)), 0644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
+
+// WriteHosts writes the hosts file of a container to a file kept by the daemon, same as WriteResolvConf.
+func WriteHosts(path string, hostname string, ip net.IP) error {
+	if err := os.WriteFile(path, []byte(Hosts(hostname, ip)), 0644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
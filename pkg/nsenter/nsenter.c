@@ -0,0 +1,168 @@
+#define _GNU_SOURCE
+#include <errno.h>
+#include <fcntl.h>
+#include <sched.h>
+#include <signal.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <grp.h>
+#include <poll.h>
+#include <sys/ioctl.h>
+#include <sys/prctl.h>
+#include <sys/stat.h>
+#include <sys/wait.h>
+#include <unistd.h>
+
+static const struct {
+	const char *name;
+	int type;
+} namespaces[] = {
+	// The user namespace goes first, it owns the other namespaces of the run.
+	{"user", CLONE_NEWUSER},
+	{"ipc", CLONE_NEWIPC},
+	{"uts", CLONE_NEWUTS},
+	{"net", CLONE_NEWNET},
+	{"pid", CLONE_NEWPID},
+	{"cgroup", CLONE_NEWCGROUP},
+	// Joining the mount namespace switches to its root, so it goes last.
+	{"mnt", CLONE_NEWNS},
+};
+
+#define NAMESPACE_COUNT (sizeof(namespaces) / sizeof(namespaces[0]))
+
+static void fail(const char *message, const char *name) {
+	fprintf(stderr, "nsenter: %s %s: %s\n", message, name, strerror(errno));
+	exit(1);
+}
+
+static int same_namespace(int fd, const char *name) {
+	char path[64];
+	struct stat target, current;
+
+	snprintf(path, sizeof(path), "/proc/self/ns/%s", name);
+	if (fstat(fd, &target) < 0 || stat(path, &current) < 0) {
+		return 0;
+	}
+	return target.st_dev == current.st_dev && target.st_ino == current.st_ino;
+}
+
+// join_namespaces returns whether the user namespace of the process differs from the current one.
+static int join_namespaces(const char *pid) {
+	int fds[NAMESPACE_COUNT];
+	int new_user_namespace = 0;
+
+	// The paths have to be opened before the mount namespace changes.
+	for (size_t i = 0; i < NAMESPACE_COUNT; i++) {
+		char path[64];
+		snprintf(path, sizeof(path), "/proc/%s/ns/%s", pid, namespaces[i].name);
+		fds[i] = open(path, O_RDONLY | O_CLOEXEC);
+		if (fds[i] < 0) {
+			fail("could not open", path);
+		}
+	}
+
+	for (size_t i = 0; i < NAMESPACE_COUNT; i++) {
+		int same = same_namespace(fds[i], namespaces[i].name);
+		if (namespaces[i].type == CLONE_NEWUSER) {
+			new_user_namespace = !same;
+		}
+		// The own user namespace cannot be joined again.
+		if (!(namespaces[i].type == CLONE_NEWUSER && same) && setns(fds[i], namespaces[i].type) < 0) {
+			fail("could not join", namespaces[i].name);
+		}
+		close(fds[i]);
+	}
+	return new_user_namespace;
+}
+
+// wait_child exits with the status of the child, a child killed by a signal kills the parent with the same one.
+static void wait_child(pid_t child) {
+	int status;
+	while (waitpid(child, &status, 0) < 0) {
+		if (errno != EINTR) {
+			fail("could not wait for", "child");
+		}
+	}
+	if (WIFSIGNALED(status)) {
+		signal(WTERMSIG(status), SIG_DFL);
+		kill(getpid(), WTERMSIG(status));
+		exit(128 + WTERMSIG(status));
+	}
+	exit(WEXITSTATUS(status));
+}
+
+// nsenter runs the program given by the arguments inside the namespaces of the process given by the environment.
+// As a constructor it runs before the runtime starts any threads, a multithreaded process cannot join a user
+// namespace. The joined pid namespace only applies to the children, the threads of the runtime could not even
+// be created, so the program runs as a child. glibc passes the arguments to the constructors.
+__attribute__((constructor)) static void nsenter(int argc, char **argv) {
+	const char *pid = getenv("_FRAGMA_NSENTER_PID");
+	if (pid == NULL || *pid == '\0' || strspn(pid, "0123456789") != strlen(pid)) {
+		return;
+	}
+	if (argc < 2) {
+		errno = EINVAL;
+		fail("no program", "given");
+	}
+	int tty = getenv("_FRAGMA_NSENTER_TTY") != NULL;
+
+	// Otherwise the processes of the run could open /proc/<pid>/exe, which is the binary of the daemon.
+	if (prctl(PR_SET_DUMPABLE, 0, 0, 0, 0) < 0) {
+		fail("could not clear", "dumpable");
+	}
+
+	int new_user_namespace = join_namespaces(pid);
+
+	// The parent keeps the write end open until it exits.
+	int alive[2];
+	if (pipe2(alive, O_CLOEXEC) < 0) {
+		fail("could not create", "pipe");
+	}
+
+	pid_t child = fork();
+	if (child < 0) {
+		fail("could not fork", "child");
+	}
+	if (child > 0) {
+		close(alive[0]);
+		wait_child(child);
+	}
+	close(alive[1]);
+
+	// The user of the daemon is not mapped in the user namespace, execve would drop the capabilities of the process.
+	// The groups cannot be changed if the namespace denies setgroups.
+	if (new_user_namespace) {
+		setgroups(0, NULL);
+		if (setresgid(0, 0, 0) < 0) {
+			fail("could not set", "gid");
+		}
+		if (setresuid(0, 0, 0) < 0) {
+			fail("could not set", "uid");
+		}
+	}
+
+	// Changing the credentials clears the parent death signal, so it is set afterwards.
+	// The program is killed along with the parent, which could have already exited.
+	if (prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0) < 0) {
+		fail("could not set", "parent death signal");
+	}
+	struct pollfd parent = {.fd = alive[0], .events = POLLIN};
+	if (poll(&parent, 1, 0) != 0) {
+		_exit(1);
+	}
+	close(alive[0]);
+
+	if (tty) {
+		if (setsid() < 0) {
+			fail("could not start", "session");
+		}
+		if (ioctl(STDIN_FILENO, TIOCSCTTY, 0) < 0) {
+			fail("could not set", "controlling terminal");
+		}
+	}
+
+	char *env[] = {NULL};
+	execve(argv[1], argv + 1, env);
+	fail("could not execute", argv[1]);
+}
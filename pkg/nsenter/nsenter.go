@@ -0,0 +1,34 @@
+// Package nsenter starts processes inside all the namespaces of another process, including its user namespace.
+// The command re-executes the current binary, whose constructor joins the namespaces before the runtime starts
+// any threads and runs the program as its child, so the program also belongs to the pid namespace.
+// A binary can only start such commands if it imports the package.
+package nsenter
+
+import "C"
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mmbednarek/fragma/pkg/process"
+)
+
+// The variables are read by the constructor in nsenter.c.
+const (
+	pidEnv = "_FRAGMA_NSENTER_PID"
+	ttyEnv = "_FRAGMA_NSENTER_TTY"
+)
+
+// Command returns a command, which runs the program inside the namespaces of the process with an empty environment.
+// The path of the program is resolved in the root of the process. The extra files are passed to the program,
+// with tty the program starts a new session with its stdin as the controlling terminal.
+// The command exits with the status of the program, which is killed along with the command.
+func Command(pid process.Pid, tty bool, extraFiles []*os.File, path string, arg ...string) *exec.Cmd {
+	cmd := exec.Command("/proc/self/exe", append([]string{path}, arg...)...)
+	cmd.Env = []string{fmt.Sprintf("%s=%d", pidEnv, pid)}
+	if tty {
+		cmd.Env = append(cmd.Env, ttyEnv+"=1")
+	}
+	cmd.ExtraFiles = extraFiles
+	return cmd
+}
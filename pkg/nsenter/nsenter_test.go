@@ -0,0 +1,23 @@
+package nsenter
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/mmbednarek/fragma/pkg/process"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("joining namespaces requires root")
+	}
+
+	// The test joins its own namespaces, the status of the program is the status of the command.
+	cmd := Command(process.Pid(os.Getpid()), false, nil, "/bin/sh", "-c", "exit 3")
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(cmd.Run(), &exitErr))
+	require.Equal(t, 3, exitErr.ExitCode())
+}
@@ -14,3 +14,11 @@ func NewRunEnvironment(path string, args []string) RunEnvironment {
 	//syscall.Open()
 	return RunEnvironment{}
 }
+
+// Spec describes an additional process, which the init of a container supervises next to the application.
+type Spec struct {
+	Name      string   `json:"name"`
+	Arguments []string `json:"arguments"`
+	// Restart starts the process again after it exits, as long as the application runs.
+	Restart bool `json:"restart,omitempty"`
+}
@@ -0,0 +1,65 @@
+package protoutil
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Formatter is implemented by messages, which have a short presentation in tables.
+type Formatter interface {
+	Format() string
+}
+
+func formatValue(desc protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch desc.Kind() {
+	case protoreflect.EnumKind:
+		enumValue := desc.Enum().Values().ByNumber(value.Enum())
+		if enumValue == nil {
+			return fmt.Sprint(value.Enum())
+		}
+		return string(enumValue.Name())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := value.Message().Interface()
+		if formatter, ok := msg.(Formatter); ok {
+			return formatter.Format()
+		}
+		return fmt.Sprint(msg)
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func formatField(desc protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if desc.IsList() {
+		list := value.List()
+		items := make([]string, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items = append(items, formatValue(desc, list.Get(i)))
+		}
+		return strings.Join(items, ",")
+	}
+	return formatValue(desc, value)
+}
+
+// FormatValueByFieldName presents any field as a string, repeated fields are joined with commas.
+// Unset and unknown fields give an empty string.
+func FormatValueByFieldName(msg proto.Message, field string) string {
+	current := msg.ProtoReflect()
+	fields := strings.Split(field, ".")
+	for i, name := range fields {
+		desc := current.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if desc == nil || !current.Has(desc) {
+			return ""
+		}
+		if i == len(fields)-1 {
+			return formatField(desc, current.Get(desc))
+		}
+		if desc.Kind() != protoreflect.MessageKind || desc.IsList() || desc.IsMap() {
+			return ""
+		}
+		current = current.Get(desc).Message()
+	}
+	return ""
+}
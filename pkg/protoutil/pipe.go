@@ -0,0 +1,39 @@
+package protoutil
+
+import (
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Pipe returns the read end of a pipe the message is written to. The message is written in the background,
+// so its size is not limited by the pipe buffer, the writer gives up once the read end is closed.
+func Pipe(msg proto.Message) (*os.File, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		defer writer.Close()
+		_, _ = writer.Write(data)
+	}()
+	return reader, nil
+}
+
+// ReadFd reads the message until the end of the descriptor, which is closed afterwards.
+func ReadFd(fd int, msg proto.Message) error {
+	file := os.NewFile(uintptr(fd), "message")
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	v1 "github.com/mmbednarek/fragma/api/fragma/core/v1"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractValueByFieldName(t *testing.T) {
@@ -18,3 +19,20 @@ func TestExtractValueByFieldName(t *testing.T) {
 	something := ExtractValueByFieldName[int64](&app, "status.size")
 	fmt.Println(something)
 }
+
+func TestFormatValueByFieldName(t *testing.T) {
+	process := v1.Process{
+		Name: "web",
+		Ports: []*v1.PortMapping{
+			{HostPort: 8080, ContainerPort: 80},
+			{HostPort: 5353, ContainerPort: 53, Protocol: v1.Protocol_PROTOCOL_UDP, HostIp: "127.0.0.1"},
+		},
+	}
+
+	require.Equal(t, "web", FormatValueByFieldName(&process, "name"))
+	require.Equal(t, "0.0.0.0:8080->80/tcp,127.0.0.1:5353->53/udp", FormatValueByFieldName(&process, "ports"))
+	require.Equal(t, "", FormatValueByFieldName(&process, "address"))
+
+	volume := v1.Volume{Status: &v1.VolumeStatus{Size: 150}}
+	require.Equal(t, "150", FormatValueByFieldName(&volume, "status.size"))
+}
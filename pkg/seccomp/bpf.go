@@ -0,0 +1,81 @@
+package seccomp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Classic BPF instruction classes and fields used by the filters.
+const (
+	BPF_LD  = 0x00
+	BPF_ALU = 0x04
+	BPF_JMP = 0x05
+	BPF_RET = 0x06
+
+	BPF_W   = 0x00
+	BPF_ABS = 0x20
+
+	BPF_AND = 0x50
+
+	BPF_JA  = 0x00
+	BPF_JEQ = 0x10
+	BPF_JGT = 0x20
+	BPF_JGE = 0x30
+
+	BPF_K = 0x00
+
+	BPF_MAXINSNS = 4096
+)
+
+// Offsets of the seccomp_data fields.
+const (
+	dataNrOffset   = 0
+	dataArchOffset = 4
+	dataArgsOffset = 16
+)
+
+// Instruction has the memory layout of struct sock_filter.
+type Instruction struct {
+	Code uint16
+	Jt   uint8
+	Jf   uint8
+	K    uint32
+}
+
+type Program []Instruction
+
+func loadAbs(offset uint32) Instruction {
+	return Instruction{Code: BPF_LD | BPF_W | BPF_ABS, K: offset}
+}
+
+func jump(op uint16, k uint32, jt uint8, jf uint8) Instruction {
+	return Instruction{Code: BPF_JMP | op | BPF_K, Jt: jt, Jf: jf, K: k}
+}
+
+func and(k uint32) Instruction {
+	return Instruction{Code: BPF_ALU | BPF_AND | BPF_K, K: k}
+}
+
+func ret(k uint32) Instruction {
+	return Instruction{Code: BPF_RET | BPF_K, K: k}
+}
+
+func (p Program) Marshal() []byte {
+	buff := bytes.Buffer{}
+	// Both supported architectures are little endian.
+	_ = binary.Write(&buff, binary.LittleEndian, p)
+	return buff.Bytes()
+}
+
+func UnmarshalProgram(data []byte) (Program, error) {
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("invalid program size: %d", len(data))
+	}
+
+	program := make(Program, len(data)/8)
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
@@ -0,0 +1,295 @@
+package seccomp
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	PR_SET_SECCOMP      = 22
+	SECCOMP_MODE_FILTER = 2
+
+	// x32SyscallBit marks syscalls of the x32 ABI, which share the x86_64 audit arch.
+	x32SyscallBit = 0x40000000
+)
+
+var (
+	ErrUnknownSyscall  = errors.New("unknown syscall")
+	ErrInvalidArgument = errors.New("invalid syscall argument")
+	ErrProgramTooLarge = errors.New("seccomp program too large")
+)
+
+type Action uint32
+
+const (
+	ActionKillProcess Action = 0x80000000
+	ActionKillThread  Action = 0x00000000
+	ActionTrap        Action = 0x00030000
+	ActionErrno       Action = 0x00050000
+	ActionLog         Action = 0x7ffc0000
+	ActionAllow       Action = 0x7fff0000
+)
+
+func (a Action) returnValue(errno uint16) uint32 {
+	if a == ActionErrno {
+		if errno == 0 {
+			errno = uint16(syscall.EPERM)
+		}
+		return uint32(a) | uint32(errno)
+	}
+	return uint32(a)
+}
+
+type Operator int
+
+const (
+	OpEqual Operator = iota
+	OpNotEqual
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+	OpMaskedEqual
+)
+
+// Arg compares a syscall argument with Value. In case of OpMaskedEqual
+// the argument is masked with Value and compared with ValueTwo.
+type Arg struct {
+	Index    uint
+	Op       Operator
+	Value    uint64
+	ValueTwo uint64
+}
+
+// Rule matches a syscall if its name is in Names and all the Args are satisfied.
+type Rule struct {
+	Names  []string
+	Action Action
+	Errno  uint16
+	Args   []Arg
+}
+
+// Profile is evaluated rule by rule, the first matching rule decides
+// the action, DefaultAction is taken if no rule matches.
+type Profile struct {
+	DefaultAction Action
+	DefaultErrno  uint16
+	Rules         []Rule
+}
+
+// DefaultProfile allows everything except the syscalls that could be used to escape the container.
+var DefaultProfile = Profile{
+	DefaultAction: ActionAllow,
+	Rules: []Rule{
+		{
+			Names: []string{
+				"acct",
+				"add_key",
+				"bpf",
+				"clock_adjtime",
+				"clock_settime",
+				"delete_module",
+				"finit_module",
+				"fsconfig",
+				"fsmount",
+				"fsopen",
+				"fspick",
+				"init_module",
+				"kexec_file_load",
+				"kexec_load",
+				"keyctl",
+				"lookup_dcookie",
+				"mount",
+				"move_mount",
+				"name_to_handle_at",
+				"open_by_handle_at",
+				"open_tree",
+				"perf_event_open",
+				"pivot_root",
+				"quotactl",
+				"reboot",
+				"request_key",
+				"setns",
+				"settimeofday",
+				"swapoff",
+				"swapon",
+				"syslog",
+				"umount2",
+				"unshare",
+				"userfaultfd",
+				"vhangup",
+			},
+			Action: ActionErrno,
+			Errno:  uint16(syscall.EPERM),
+		},
+	},
+}
+
+func SyscallNumber(name string) (uint32, error) {
+	nr, ok := syscallNumbers[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSyscall, name)
+	}
+	return nr, nil
+}
+
+// block is a part of the program, which jumps to its end if the rule does not match.
+type block struct {
+	insns   Program
+	patches []patch
+}
+
+type patch struct {
+	index  int
+	onTrue bool
+}
+
+func (b *block) add(insn Instruction) {
+	b.insns = append(b.insns, insn)
+}
+
+// addFailJump adds a conditional jump, that leaves the block on the given condition result.
+func (b *block) addFailJump(insn Instruction, onTrue bool) {
+	b.patches = append(b.patches, patch{index: len(b.insns), onTrue: onTrue})
+	b.add(insn)
+}
+
+func (b *block) finish() (Program, error) {
+	for _, p := range b.patches {
+		offset := len(b.insns) - p.index - 1
+		if offset > 0xff {
+			return nil, ErrProgramTooLarge
+		}
+		if p.onTrue {
+			b.insns[p.index].Jt = uint8(offset)
+		} else {
+			b.insns[p.index].Jf = uint8(offset)
+		}
+	}
+	return b.insns, nil
+}
+
+func (b *block) compileArg(arg Arg) error {
+	if arg.Index > 5 {
+		return fmt.Errorf("%w: index %d", ErrInvalidArgument, arg.Index)
+	}
+
+	lowOffset := uint32(dataArgsOffset + 8*arg.Index)
+	highOffset := lowOffset + 4
+	high, low := uint32(arg.Value>>32), uint32(arg.Value)
+
+	switch arg.Op {
+	case OpEqual:
+		b.add(loadAbs(highOffset))
+		b.addFailJump(jump(BPF_JEQ, high, 0, 0), false)
+		b.add(loadAbs(lowOffset))
+		b.addFailJump(jump(BPF_JEQ, low, 0, 0), false)
+	case OpNotEqual:
+		b.add(loadAbs(highOffset))
+		b.add(jump(BPF_JEQ, high, 0, 2))
+		b.add(loadAbs(lowOffset))
+		b.addFailJump(jump(BPF_JEQ, low, 0, 0), true)
+	case OpGreater, OpGreaterEqual:
+		b.add(loadAbs(highOffset))
+		b.add(jump(BPF_JGT, high, 3, 0))
+		b.addFailJump(jump(BPF_JEQ, high, 0, 0), false)
+		b.add(loadAbs(lowOffset))
+		if arg.Op == OpGreater {
+			b.addFailJump(jump(BPF_JGT, low, 0, 0), false)
+		} else {
+			b.addFailJump(jump(BPF_JGE, low, 0, 0), false)
+		}
+	case OpLess, OpLessEqual:
+		b.add(loadAbs(highOffset))
+		b.addFailJump(jump(BPF_JGT, high, 0, 0), true)
+		b.add(jump(BPF_JEQ, high, 0, 2))
+		b.add(loadAbs(lowOffset))
+		if arg.Op == OpLess {
+			b.addFailJump(jump(BPF_JGE, low, 0, 0), true)
+		} else {
+			b.addFailJump(jump(BPF_JGT, low, 0, 0), true)
+		}
+	case OpMaskedEqual:
+		b.add(loadAbs(highOffset))
+		b.add(and(high))
+		b.addFailJump(jump(BPF_JEQ, uint32(arg.ValueTwo>>32), 0, 0), false)
+		b.add(loadAbs(lowOffset))
+		b.add(and(low))
+		b.addFailJump(jump(BPF_JEQ, uint32(arg.ValueTwo), 0, 0), false)
+	default:
+		return fmt.Errorf("%w: unknown operator %d", ErrInvalidArgument, arg.Op)
+	}
+	return nil
+}
+
+func compileRule(nr uint32, rule Rule) (Program, error) {
+	b := block{}
+	b.add(loadAbs(dataNrOffset))
+	b.addFailJump(jump(BPF_JEQ, nr, 0, 0), false)
+	for _, arg := range rule.Args {
+		if err := b.compileArg(arg); err != nil {
+			return nil, err
+		}
+	}
+	b.add(ret(rule.Action.returnValue(rule.Errno)))
+	return b.finish()
+}
+
+// Compile translates the profile into a BPF program for the native architecture.
+func Compile(profile Profile) (Program, error) {
+	program := Program{
+		loadAbs(dataArchOffset),
+		jump(BPF_JEQ, nativeArch, 1, 0),
+		ret(uint32(ActionKillProcess)),
+		loadAbs(dataNrOffset),
+		jump(BPF_JGE, x32SyscallBit, 0, 1),
+		ret(uint32(ActionKillProcess)),
+	}
+
+	for _, rule := range profile.Rules {
+		for _, name := range rule.Names {
+			nr, err := SyscallNumber(name)
+			if err != nil {
+				return nil, err
+			}
+
+			insns, err := compileRule(nr, rule)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", name, err)
+			}
+			program = append(program, insns...)
+		}
+	}
+
+	program = append(program, ret(profile.DefaultAction.returnValue(profile.DefaultErrno)))
+	if len(program) > BPF_MAXINSNS {
+		return nil, ErrProgramTooLarge
+	}
+
+	return program, nil
+}
+
+type sockFprog struct {
+	Len    uint16
+	Filter *Instruction
+}
+
+// Load installs the filter for the calling thread. The caller must either
+// have CAP_SYS_ADMIN or have no_new_privs set.
+func Load(program Program) error {
+	if len(program) == 0 {
+		return errors.New("empty seccomp program")
+	}
+
+	prog := sockFprog{
+		Len:    uint16(len(program)),
+		Filter: &program[0],
+	}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, PR_SET_SECCOMP, SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package seccomp
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// run interprets the program with the given syscall data.
+func run(t *testing.T, program Program, arch uint32, nr uint32, args ...uint64) uint32 {
+	data := make([]byte, 64)
+	binary.LittleEndian.PutUint32(data[dataNrOffset:], nr)
+	binary.LittleEndian.PutUint32(data[dataArchOffset:], arch)
+	for i, arg := range args {
+		binary.LittleEndian.PutUint64(data[dataArgsOffset+8*i:], arg)
+	}
+
+	var acc uint32
+	for pc := 0; pc < len(program); pc++ {
+		insn := program[pc]
+		switch insn.Code {
+		case BPF_LD | BPF_W | BPF_ABS:
+			acc = binary.LittleEndian.Uint32(data[insn.K:])
+		case BPF_ALU | BPF_AND | BPF_K:
+			acc &= insn.K
+		case BPF_JMP | BPF_JEQ | BPF_K, BPF_JMP | BPF_JGT | BPF_K, BPF_JMP | BPF_JGE | BPF_K:
+			var cond bool
+			switch insn.Code &^ (BPF_JMP | BPF_K) {
+			case BPF_JEQ:
+				cond = acc == insn.K
+			case BPF_JGT:
+				cond = acc > insn.K
+			case BPF_JGE:
+				cond = acc >= insn.K
+			}
+			if cond {
+				pc += int(insn.Jt)
+			} else {
+				pc += int(insn.Jf)
+			}
+		case BPF_RET | BPF_K:
+			return insn.K
+		default:
+			t.Fatalf("unexpected instruction: %x", insn.Code)
+		}
+	}
+	t.Fatal("program did not return")
+	return 0
+}
+
+func nr(t *testing.T, name string) uint32 {
+	result, err := SyscallNumber(name)
+	require.NoError(t, err)
+	return result
+}
+
+func TestCompile_DefaultProfile(t *testing.T) {
+	program, err := Compile(DefaultProfile)
+	require.NoError(t, err)
+
+	eperm := uint32(ActionErrno) | uint32(syscall.EPERM)
+	require.Equal(t, eperm, run(t, program, nativeArch, nr(t, "mount")))
+	require.Equal(t, eperm, run(t, program, nativeArch, nr(t, "kexec_load")))
+	require.Equal(t, eperm, run(t, program, nativeArch, nr(t, "setns")))
+	require.Equal(t, uint32(ActionAllow), run(t, program, nativeArch, nr(t, "read")))
+	require.Equal(t, uint32(ActionKillProcess), run(t, program, 0x1234, nr(t, "read")))
+	require.Equal(t, uint32(ActionKillProcess), run(t, program, nativeArch, x32SyscallBit|nr(t, "mount")))
+}
+
+func TestCompile_Arguments(t *testing.T) {
+	program, err := Compile(Profile{
+		DefaultAction: ActionErrno,
+		DefaultErrno:  uint16(syscall.ENOSYS),
+		Rules: []Rule{
+			{Names: []string{"write"}, Action: ActionAllow, Args: []Arg{{Index: 0, Op: OpLessEqual, Value: 2}}},
+			{Names: []string{"personality"}, Action: ActionAllow, Args: []Arg{{Index: 0, Op: OpEqual, Value: 0xffffffff}}},
+			{Names: []string{"kill"}, Action: ActionAllow, Args: []Arg{{Index: 1, Op: OpNotEqual, Value: 9}}},
+			{Names: []string{"mmap"}, Action: ActionAllow, Args: []Arg{{Index: 2, Op: OpMaskedEqual, Value: 0x4, ValueTwo: 0}}},
+			{Names: []string{"lseek"}, Action: ActionAllow, Args: []Arg{{Index: 1, Op: OpGreater, Value: 1 << 32}}},
+		},
+	})
+	require.NoError(t, err)
+
+	enosys := uint32(ActionErrno) | uint32(syscall.ENOSYS)
+	allow := uint32(ActionAllow)
+
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "write"), 1))
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "write"), 2))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "write"), 3))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "write"), 1<<32|1))
+
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "personality"), 0xffffffff))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "personality"), 0x1ffffffff))
+
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "kill"), 1, 15))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "kill"), 1, 9))
+
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "mmap"), 0, 0, 0x3))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "mmap"), 0, 0, 0x7))
+
+	require.Equal(t, allow, run(t, program, nativeArch, nr(t, "lseek"), 0, 1<<32+1))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "lseek"), 0, 1<<32))
+	require.Equal(t, enosys, run(t, program, nativeArch, nr(t, "lseek"), 0, 5))
+}
+
+func TestCompile_UnknownSyscall(t *testing.T) {
+	_, err := Compile(Profile{Rules: []Rule{{Names: []string{"not_a_syscall"}}}})
+	require.Error(t, err)
+}
+
+func TestProgram_Marshal(t *testing.T) {
+	program, err := Compile(DefaultProfile)
+	require.NoError(t, err)
+
+	result, err := UnmarshalProgram(program.Marshal())
+	require.NoError(t, err)
+	require.Equal(t, program, result)
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger/v3"
@@ -58,6 +59,9 @@ func (s Storage) ReadObject(typeName string, name string) (model.Object, error)
 
 	err := s.db.View(func(txn *badger.Txn) error {
 		value, err := txn.Get(makeKeyWithTypeUrl("type.googleapis.com/"+typeName, name))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return fmt.Errorf("%w: %s", model.ErrObjectNotFound, name)
+		}
 		if err != nil {
 			return fmt.Errorf("trans.Get: %w", err)
 		}
@@ -140,6 +144,52 @@ func (s Storage) RemoveObject(typeName string, name string) error {
 	return nil
 }
 
+// WriteValue stores a raw value, which is not an API object. Keys of such
+// values should not start with the type.googleapis.com/ prefix.
+func (s Storage) WriteValue(key string, value []byte) error {
+	err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), value)
+	})
+	if err != nil {
+		return fmt.Errorf("s.db.Update: %w", err)
+	}
+	return nil
+}
+
+func (s Storage) ReadAllValues(prefix string) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{
+			Prefix: []byte(prefix),
+		})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("item.ValueCopy: %w", err)
+			}
+			result[string(item.Key())] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s.db.View: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s Storage) RemoveValue(key string) error {
+	err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("s.db.Update: %w", err)
+	}
+	return nil
+}
+
 func makeKey(obj *core.Object) []byte {
 	if obj.Metadata == nil {
 		return nil
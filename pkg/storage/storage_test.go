@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"testing"
 
 	v1 "github.com/mmbednarek/fragma/api/fragma/core/v1"
@@ -16,11 +17,12 @@ func Test_makeKey(t *testing.T) {
 	}
 
 	obj := model.Object{
-		Version: "v1",
-		Name:    "App",
-		Kind:    "Application",
-		Labels:  map[string]string{},
-		Spec:    &app,
+		Kind: "Application",
+		Metadata: model.Metadata{
+			Name:   "App",
+			Labels: map[string]string{},
+		},
+		Spec: model.Spec{Message: &app},
 	}
 
 	protoObj, err := obj.ToProto()
@@ -40,11 +42,12 @@ func TestStorage_WriteObject(t *testing.T) {
 	}
 
 	obj := model.Object{
-		Version: "v1",
-		Name:    "App",
-		Kind:    "Application",
-		Labels:  map[string]string{"option": "true"},
-		Spec:    &app,
+		Kind: "Application",
+		Metadata: model.Metadata{
+			Name:   "App",
+			Labels: map[string]string{"option": "true"},
+		},
+		Spec: model.Spec{Message: &app},
 	}
 
 	err = store.WriteObject(&obj)
@@ -53,9 +56,25 @@ func TestStorage_WriteObject(t *testing.T) {
 	dbObj, err := store.ReadObject("fragma.core.v1.Application", "App")
 	require.NoError(t, err)
 
-	require.Equal(t, obj.Version, dbObj.Version)
-	require.Equal(t, obj.Name, dbObj.Name)
+	require.Equal(t, obj.Metadata.Name, dbObj.Metadata.Name)
 	require.Equal(t, obj.Kind, dbObj.Kind)
-	require.Equal(t, obj.Labels, dbObj.Labels)
+	require.Equal(t, obj.Metadata.Labels, dbObj.Metadata.Labels)
 	require.True(t, proto.Equal(&app, dbObj.Spec))
+
+	_, err = store.ReadObject("fragma.core.v1.Application", "Missing")
+	require.True(t, errors.Is(err, model.ErrObjectNotFound))
+}
+
+func TestStorage_Values(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.WriteValue("test/a", []byte("1")))
+	require.NoError(t, store.WriteValue("test/b", []byte("2")))
+	require.NoError(t, store.WriteValue("other/c", []byte("3")))
+	require.NoError(t, store.RemoveValue("test/b"))
+
+	values, err := store.ReadAllValues("test/")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"test/a": []byte("1")}, values)
 }
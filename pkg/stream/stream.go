@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Protocol is the value of the Upgrade header of the connections carrying frames.
+const Protocol = "fragma-stream"
+
+type FrameType byte
+
+const (
+	FrameStdin FrameType = iota
+	FrameStdout
+	FrameStderr
+	FrameCloseStdin
+	FrameResize
+	FrameExit
+	FrameError
+)
+
+// MaxFrameSize limits the payload of a single frame.
+const MaxFrameSize = 1 << 20
+
+const headerSize = 5
+
+var ErrFrameTooLarge = errors.New("frame too large")
+
+type Frame struct {
+	Type    FrameType
+	Payload []byte
+}
+
+// Conn exchanges frames over a connection, a frame consists of the type,
+// the big endian length of the payload and the payload. Writes are safe for concurrent use.
+type Conn struct {
+	r io.Reader
+	w io.Writer
+
+	mu sync.Mutex
+}
+
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{r: r, w: w}
+}
+
+// Close closes the underlying writer, if it can be closed.
+func (c *Conn) Close() error {
+	if closer, ok := c.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (c *Conn) WriteFrame(frameType FrameType, payload []byte) error {
+	if len(payload) > MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	header := make([]byte, headerSize)
+	header[0] = byte(frameType)
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.w.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.w.Write(payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Conn) ReadFrame() (Frame, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return Frame{}, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > MaxFrameSize {
+		return Frame{}, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return Frame{}, err
+	}
+	return Frame{Type: FrameType(header[0]), Payload: payload}, nil
+}
+
+// Writer returns a writer sending the data in frames of the given type.
+func (c *Conn) Writer(frameType FrameType) io.Writer {
+	return frameWriter{conn: c, frameType: frameType}
+}
+
+type frameWriter struct {
+	conn      *Conn
+	frameType FrameType
+}
+
+func (w frameWriter) Write(data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > MaxFrameSize {
+			chunk = chunk[:MaxFrameSize]
+		}
+		if err := w.conn.WriteFrame(w.frameType, chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+	return written, nil
+}
+
+func EncodeResize(rows uint16, cols uint16) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload, rows)
+	binary.BigEndian.PutUint16(payload[2:], cols)
+	return payload
+}
+
+func DecodeResize(payload []byte) (uint16, uint16, error) {
+	if len(payload) != 4 {
+		return 0, 0, fmt.Errorf("invalid resize payload: %d bytes", len(payload))
+	}
+	return binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:]), nil
+}
+
+func EncodeExit(code int32) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(code))
+	return payload
+}
+
+func DecodeExit(payload []byte) (int32, error) {
+	if len(payload) != 4 {
+		return 0, fmt.Errorf("invalid exit payload: %d bytes", len(payload))
+	}
+	return int32(binary.BigEndian.Uint32(payload)), nil
+}
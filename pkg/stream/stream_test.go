@@ -0,0 +1,43 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_Frames(t *testing.T) {
+	var buff bytes.Buffer
+	conn := NewConn(&buff, &buff)
+
+	_, err := conn.Writer(FrameStdout).Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteFrame(FrameResize, EncodeResize(24, 80)))
+	require.NoError(t, conn.WriteFrame(FrameExit, EncodeExit(-1)))
+
+	frame, err := conn.ReadFrame()
+	require.NoError(t, err)
+	require.Equal(t, FrameStdout, frame.Type)
+	require.Equal(t, []byte("hello"), frame.Payload)
+
+	frame, err = conn.ReadFrame()
+	require.NoError(t, err)
+	rows, cols, err := DecodeResize(frame.Payload)
+	require.NoError(t, err)
+	require.Equal(t, uint16(24), rows)
+	require.Equal(t, uint16(80), cols)
+
+	frame, err = conn.ReadFrame()
+	require.NoError(t, err)
+	code, err := DecodeExit(frame.Payload)
+	require.NoError(t, err)
+	require.Equal(t, int32(-1), code)
+}
+
+func TestConn_FrameTooLarge(t *testing.T) {
+	buff := bytes.NewBuffer([]byte{byte(FrameStdin), 0xff, 0xff, 0xff, 0xff})
+	_, err := NewConn(buff, buff).ReadFrame()
+	require.True(t, errors.Is(err, ErrFrameTooLarge))
+}